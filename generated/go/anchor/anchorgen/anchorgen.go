@@ -0,0 +1,688 @@
+// Package anchorgen generates typed Go bindings for an Anchor program from its
+// IDL. The cmd/anchor-gen command wraps it.
+//
+// The generated package has:
+//
+//   - a struct for every IDL type, with MarshalBorsh and UnmarshalBorsh
+//     methods; enums without data are uint8 types, and enums with data are
+//     structs holding a Kind and one pointer per variant
+//   - MarshalAccount and UnmarshalAccount methods on account types, which
+//     add and check the discriminator, and Fetch<Account> and
+//     Fetch<Account>Accounts helpers that call GetAccountInfo and
+//     GetProgramAccounts with a discriminator memcmp filter
+//   - New<Instruction>Instruction builders returning a solana.Instruction
+//   - DecodeEvent and DecodeEvents for the program's events
+//   - an ErrorCode type with a constant per program error
+package anchorgen
+
+import (
+	"bytes"
+	"fmt"
+	"go/format"
+	"regexp"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/solana-rpc/client/anchor"
+)
+
+// Generate returns the source of a Go package named pkg with bindings for
+// idl. The IDL must have the program's address. Zero-copy types and 256-bit
+// integers are not supported.
+func Generate(idl *anchor.IDL, pkg string) ([]byte, error) {
+	if idl.Address == "" {
+		return nil, fmt.Errorf("anchorgen: IDL has no program address")
+	}
+	if pkg == "" {
+		pkg = PackageName(idl.Name)
+	}
+	g := &generator{idl: idl, pkg: pkg}
+
+	steps := []func() error{g.programID, g.types, g.accounts, g.instructions, g.events, g.errorCodes}
+	for _, step := range steps {
+		if err := step(); err != nil {
+			return nil, err
+		}
+	}
+
+	var out bytes.Buffer
+	fmt.Fprintf(&out, "// Code generated by anchor-gen from the %s IDL. DO NOT EDIT.\n\n", idl.Name)
+	fmt.Fprintf(&out, "// Package %s has bindings for the %s Anchor program.\n", pkg, idl.Name)
+	fmt.Fprintf(&out, "package %s\n\n", pkg)
+	out.WriteString("import (\n")
+	body := g.buf.String()
+	for _, imp := range []struct{ name, path string }{
+		{"bytes", "bytes"},
+		{"context", "context"},
+		{"errors", "errors"},
+		{"fmt", "fmt"},
+		{"solana", "github.com/solana-rpc/client"},
+		{"anchor", "github.com/solana-rpc/client/anchor"},
+		{"borsh", "github.com/solana-rpc/client/borsh"},
+	} {
+		if !regexp.MustCompile(`(^|[^\w.])` + imp.name + `\.[A-Z]`).MatchString(body) {
+			continue
+		}
+		if imp.name == "solana" {
+			// Separate the module's packages from the standard library's.
+			out.WriteString("\n")
+			fmt.Fprintf(&out, "solana %q\n", imp.path)
+		} else {
+			fmt.Fprintf(&out, "%q\n", imp.path)
+		}
+	}
+	out.WriteString(")\n")
+	out.WriteString(body)
+
+	src, err := format.Source(out.Bytes())
+	if err != nil {
+		return nil, fmt.Errorf("anchorgen: generated invalid Go: %w", err)
+	}
+	return src, nil
+}
+
+// PackageName derives a Go package name from a program name, such as
+// "token_vault" to "tokenvault".
+func PackageName(program string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(program) {
+		if unicode.IsLetter(r) || unicode.IsDigit(r) && b.Len() > 0 {
+			b.WriteRune(r)
+		}
+	}
+	if b.Len() == 0 {
+		return "program"
+	}
+	return b.String()
+}
+
+// GoName converts an IDL name in snake_case or camelCase to an exported Go
+// name.
+func GoName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	}) {
+		if initialism := strings.ToUpper(part); commonInitialisms[initialism] {
+			b.WriteString(initialism)
+			continue
+		}
+		runes := []rune(part)
+		runes[0] = unicode.ToUpper(runes[0])
+		b.WriteString(string(runes))
+	}
+	s := b.String()
+	if s == "" || unicode.IsDigit(rune(s[0])) {
+		s = "X" + s
+	}
+	return s
+}
+
+var commonInitialisms = map[string]bool{"ID": true, "NFT": true, "PDA": true, "URI": true, "URL": true}
+
+type generator struct {
+	idl *anchor.IDL
+	pkg string
+	buf bytes.Buffer
+
+	// temps numbers the temporaries declared in generated functions.
+	temps int
+}
+
+// p writes a line of generated code. Indentation is left to gofmt.
+func (g *generator) p(format string, args ...any) {
+	fmt.Fprintf(&g.buf, format, args...)
+	g.buf.WriteByte('\n')
+}
+
+// doc writes docs as a comment, or fallback if there are none.
+func (g *generator) doc(docs []string, fallback string, args ...any) {
+	if len(docs) == 0 {
+		if fallback == "" {
+			return
+		}
+		docs = []string{fmt.Sprintf(fallback, args...)}
+	}
+	for _, line := range docs {
+		g.p("// %s", line)
+	}
+}
+
+func (g *generator) temp(prefix string) string {
+	g.temps++
+	return fmt.Sprintf("%s%d", prefix, g.temps)
+}
+
+func (g *generator) programID() error {
+	g.p("")
+	g.p("// ProgramID is the program's address. Change it to use a deployment at")
+	g.p("// another address.")
+	g.p("var ProgramID solana.Pubkey = %q", g.idl.Address)
+	return nil
+}
+
+func (g *generator) types() error {
+	for _, def := range g.idl.Types {
+		if def.Serialization != "borsh" {
+			return fmt.Errorf("anchorgen: type %s uses %s serialization, which is not supported", def.Name, def.Serialization)
+		}
+		name := GoName(def.Name)
+		var err error
+		switch def.Kind {
+		case "struct":
+			err = g.structType(name, def.Docs, def.Fields)
+		case "enum":
+			err = g.enumType(name, def.Docs, def.Variants)
+		case "alias":
+			var goType string
+			if goType, err = g.goType(def.Alias); err == nil {
+				g.p("")
+				g.doc(def.Docs, "")
+				g.p("type %s = %s", name, goType)
+			}
+		default:
+			err = fmt.Errorf("unsupported kind %q", def.Kind)
+		}
+		if err != nil {
+			return fmt.Errorf("anchorgen: type %s: %w", def.Name, err)
+		}
+	}
+	return nil
+}
+
+func fieldName(f anchor.IDLField, i int) string {
+	if f.Name == "" {
+		return fmt.Sprintf("Field%d", i)
+	}
+	return GoName(f.Name)
+}
+
+func (g *generator) structType(name string, docs []string, fields []anchor.IDLField) error {
+	g.p("")
+	g.doc(docs, "")
+	g.p("type %s struct {", name)
+	for i, f := range fields {
+		goType, err := g.goType(&f.Type)
+		if err != nil {
+			return fmt.Errorf("%s: %w", f.Name, err)
+		}
+		g.doc(f.Docs, "")
+		g.p("%s %s", fieldName(f, i), goType)
+	}
+	g.p("}")
+
+	g.p("")
+	g.p("func (v %s) MarshalBorsh(e *borsh.Encoder) error {", name)
+	for i, f := range fields {
+		g.encode(&f.Type, "v."+fieldName(f, i))
+	}
+	g.p("return nil")
+	g.p("}")
+
+	g.p("")
+	g.p("func (v *%s) UnmarshalBorsh(d *borsh.Decoder) error {", name)
+	if len(fields) > 0 {
+		g.p("var err error")
+	}
+	for i, f := range fields {
+		if err := g.decode(&f.Type, "v."+fieldName(f, i)); err != nil {
+			return err
+		}
+	}
+	g.p("return nil")
+	g.p("}")
+	return nil
+}
+
+func (g *generator) enumType(name string, docs []string, variants []anchor.IDLVariant) error {
+	withData := false
+	for _, v := range variants {
+		withData = withData || len(v.Fields) > 0
+	}
+
+	if !withData {
+		g.p("")
+		g.doc(docs, "")
+		g.p("type %s uint8", name)
+		g.p("")
+		g.p("const (")
+		for i, v := range variants {
+			if i == 0 {
+				g.p("%s%s %s = iota", name, GoName(v.Name), name)
+			} else {
+				g.p("%s%s", name, GoName(v.Name))
+			}
+		}
+		g.p(")")
+		g.p("")
+		g.p("func (v %s) String() string {", name)
+		g.p("switch v {")
+		for _, v := range variants {
+			g.p("case %s%s:", name, GoName(v.Name))
+			g.p("return %q", v.Name)
+		}
+		g.p("}")
+		g.p("return fmt.Sprintf(\"%s(%%d)\", uint8(v))", name)
+		g.p("}")
+		g.p("")
+		g.p("func (v %s) MarshalBorsh(e *borsh.Encoder) error {", name)
+		g.p("e.U8(uint8(v))")
+		g.p("return nil")
+		g.p("}")
+		g.p("")
+		g.p("func (v *%s) UnmarshalBorsh(d *borsh.Decoder) error {", name)
+		g.p("b, err := d.U8()")
+		g.p("if err != nil {")
+		g.p("return err")
+		g.p("}")
+		g.p("if int(b) >= %d {", len(variants))
+		g.p("return fmt.Errorf(\"%s: unknown %s variant %%d\", b)", g.pkg, name)
+		g.p("}")
+		g.p("*v = %s(b)", name)
+		g.p("return nil")
+		g.p("}")
+		return nil
+	}
+
+	kind := name + "Kind"
+	g.p("")
+	if len(docs) > 0 {
+		g.doc(docs, "")
+		g.p("//")
+	}
+	g.p("// %s is an enum: Kind says which variant it is, and the field named after", name)
+	g.p("// the variant holds its data, if it has any.")
+	g.p("type %s struct {", name)
+	g.p("Kind %s", kind)
+	for _, v := range variants {
+		if len(v.Fields) > 0 {
+			g.p("%s *%s%s", GoName(v.Name), name, GoName(v.Name))
+		}
+	}
+	g.p("}")
+	g.p("")
+	g.p("// %s is a variant of %s.", kind, name)
+	g.p("type %s uint8", kind)
+	g.p("")
+	g.p("const (")
+	for i, v := range variants {
+		if i == 0 {
+			g.p("%s%s %s = iota", kind, GoName(v.Name), kind)
+		} else {
+			g.p("%s%s", kind, GoName(v.Name))
+		}
+	}
+	g.p(")")
+
+	for _, v := range variants {
+		if len(v.Fields) == 0 {
+			continue
+		}
+		if err := g.structType(name+GoName(v.Name), nil, v.Fields); err != nil {
+			return fmt.Errorf("%s: %w", v.Name, err)
+		}
+	}
+
+	g.p("")
+	g.p("func (v %s) MarshalBorsh(e *borsh.Encoder) error {", name)
+	g.p("e.U8(uint8(v.Kind))")
+	g.p("switch v.Kind {")
+	for _, v := range variants {
+		if len(v.Fields) == 0 {
+			continue
+		}
+		field := GoName(v.Name)
+		g.p("case %s%s:", kind, field)
+		g.p("if v.%s == nil {", field)
+		g.p("return errors.New(\"%s: %s is %s but %s is nil\")", g.pkg, name, v.Name, field)
+		g.p("}")
+		g.p("return v.%s.MarshalBorsh(e)", field)
+	}
+	g.p("}")
+	g.p("return nil")
+	g.p("}")
+	g.p("")
+	g.p("func (v *%s) UnmarshalBorsh(d *borsh.Decoder) error {", name)
+	g.p("b, err := d.U8()")
+	g.p("if err != nil {")
+	g.p("return err")
+	g.p("}")
+	g.p("*v = %s{Kind: %s(b)}", name, kind)
+	g.p("switch v.Kind {")
+	for _, v := range variants {
+		field := GoName(v.Name)
+		g.p("case %s%s:", kind, field)
+		if len(v.Fields) > 0 {
+			g.p("v.%s = new(%s%s)", field, name, field)
+			g.p("return v.%s.UnmarshalBorsh(d)", field)
+		}
+	}
+	g.p("default:")
+	g.p("return fmt.Errorf(\"%s: unknown %s variant %%d\", b)", g.pkg, name)
+	g.p("}")
+	g.p("return nil")
+	g.p("}")
+	return nil
+}
+
+func (g *generator) accounts() error {
+	for _, account := range g.idl.Accounts {
+		if _, ok := g.idl.TypeDef(account.Name); !ok {
+			return fmt.Errorf("anchorgen: account %s has no type", account.Name)
+		}
+		name := GoName(account.Name)
+		disc := name + "AccountDiscriminator"
+
+		g.p("")
+		g.p("// %s prefixes the data of %s accounts.", disc, name)
+		g.p("var %s = %s", disc, byteSlice(account.Discriminator))
+		g.p("")
+		g.p("// MarshalAccount encodes v as %s account data, discriminator first.", name)
+		g.p("func (v %s) MarshalAccount() ([]byte, error) {", name)
+		g.p("e := borsh.NewEncoder()")
+		g.p("e.Write(%s)", disc)
+		g.p("if err := v.MarshalBorsh(e); err != nil {")
+		g.p("return nil, err")
+		g.p("}")
+		g.p("return e.Bytes(), nil")
+		g.p("}")
+		g.p("")
+		g.p("// UnmarshalAccount decodes %s account data after checking its", name)
+		g.p("// discriminator. Bytes past the account's layout are ignored.")
+		g.p("func (v *%s) UnmarshalAccount(data []byte) error {", name)
+		g.p("if !bytes.HasPrefix(data, %s) {", disc)
+		g.p("return fmt.Errorf(\"%%w: not a %s account\", anchor.ErrUnknownDiscriminator)", name)
+		g.p("}")
+		g.p("return v.UnmarshalBorsh(borsh.NewDecoder(data[len(%s):]))", disc)
+		g.p("}")
+		g.p("")
+		g.p("// Fetch%s fetches and decodes the %s account at address.", name, name)
+		g.p("func Fetch%s(ctx context.Context, client solana.RPC, address solana.Pubkey) (*%s, error) {", name, name)
+		g.p("resp, err := client.GetAccountInfo(ctx, address, &solana.GetAccountInfoConfig{Encoding: solana.EncodingBase64})")
+		g.p("if err != nil {")
+		g.p("return nil, err")
+		g.p("}")
+		g.p("if resp.Value.Owner == \"\" {")
+		g.p("return nil, fmt.Errorf(\"%%w: %%s\", anchor.ErrAccountNotFound, address)")
+		g.p("}")
+		g.p("if resp.Value.Owner != ProgramID {")
+		g.p("return nil, fmt.Errorf(\"%s: account %%s is owned by %%s, not the program\", address, resp.Value.Owner)", g.pkg)
+		g.p("}")
+		g.p("v := new(%s)", name)
+		g.p("if err := v.UnmarshalAccount(resp.Value.Data.Bytes); err != nil {")
+		g.p("return nil, fmt.Errorf(\"%s: account %%s: %%w\", address, err)", g.pkg)
+		g.p("}")
+		g.p("return v, nil")
+		g.p("}")
+		g.p("")
+		g.p("// Fetch%sAccounts fetches and decodes all %s accounts of the program", name, name)
+		g.p("// that also match filters, whose offsets count the discriminator.")
+		g.p("func Fetch%sAccounts(ctx context.Context, client solana.RPC, filters ...solana.AccountFilter) ([]anchor.ProgramAccount[*%s], error) {", name, name)
+		g.p("accounts, err := client.GetProgramAccounts(ctx, ProgramID, &solana.GetProgramAccountsConfig{")
+		g.p("Encoding: solana.EncodingBase64,")
+		g.p("Filters:  append([]solana.AccountFilter{solana.MemcmpBytes(0, %s)}, filters...),", disc)
+		g.p("})")
+		g.p("if err != nil {")
+		g.p("return nil, err")
+		g.p("}")
+		g.p("out := make([]anchor.ProgramAccount[*%s], 0, len(accounts))", name)
+		g.p("for _, a := range accounts {")
+		g.p("v := new(%s)", name)
+		g.p("if err := v.UnmarshalAccount(a.Account.Data.Bytes); err != nil {")
+		g.p("return nil, fmt.Errorf(\"%s: account %%s: %%w\", a.Pubkey, err)", g.pkg)
+		g.p("}")
+		g.p("out = append(out, anchor.ProgramAccount[*%s]{Pubkey: a.Pubkey, Account: v})", name)
+		g.p("}")
+		g.p("return out, nil")
+		g.p("}")
+	}
+	return nil
+}
+
+// instructionAccount is an IDL instruction account flattened out of its
+// groups.
+type instructionAccount struct {
+	field string
+	anchor.IDLInstructionAccount
+}
+
+func flattenAccounts(prefix string, items []anchor.IDLInstructionAccount) []instructionAccount {
+	var out []instructionAccount
+	for _, item := range items {
+		if len(item.Accounts) > 0 {
+			out = append(out, flattenAccounts(prefix+GoName(item.Name), item.Accounts)...)
+			continue
+		}
+		out = append(out, instructionAccount{field: prefix + GoName(item.Name), IDLInstructionAccount: item})
+	}
+	return out
+}
+
+func (g *generator) instructions() error {
+	needOptional := false
+	for _, ix := range g.idl.Instructions {
+		name := GoName(ix.Name)
+		disc := name + "InstructionDiscriminator"
+		accountsType, argsType := name+"Accounts", name+"Args"
+		accounts := flattenAccounts("", ix.Accounts)
+
+		g.p("")
+		g.p("// %s starts the data of %s instructions.", disc, ix.Name)
+		g.p("var %s = %s", disc, byteSlice(ix.Discriminator))
+
+		g.p("")
+		g.p("// %s are the accounts the %s instruction takes.", accountsType, ix.Name)
+		g.p("type %s struct {", accountsType)
+		for _, a := range accounts {
+			if a.Address != "" {
+				continue
+			}
+			g.doc(a.Docs, "")
+			var notes []string
+			if a.Writable {
+				notes = append(notes, "writable")
+			}
+			if a.Signer {
+				notes = append(notes, "signer")
+			}
+			if a.Optional {
+				notes = append(notes, "optional: leave empty to omit")
+			}
+			if len(notes) > 0 {
+				g.p("%s solana.Pubkey // %s", a.field, strings.Join(notes, ", "))
+			} else {
+				g.p("%s solana.Pubkey", a.field)
+			}
+		}
+		g.p("")
+		g.p("// RemainingAccounts are passed after the accounts above.")
+		g.p("RemainingAccounts []solana.AccountMeta")
+		g.p("}")
+
+		if len(ix.Args) > 0 {
+			if err := g.structType(argsType, []string{fmt.Sprintf("%s are the arguments of the %s instruction.", argsType, ix.Name)}, ix.Args); err != nil {
+				return fmt.Errorf("anchorgen: instruction %s: %w", ix.Name, err)
+			}
+		}
+
+		g.p("")
+		g.doc(ix.Docs, "New%sInstruction builds the %s instruction.", name, ix.Name)
+		if len(ix.Args) > 0 {
+			g.p("func New%sInstruction(accounts %s, args %s) (solana.Instruction, error) {", name, accountsType, argsType)
+		} else {
+			g.p("func New%sInstruction(accounts %s) (solana.Instruction, error) {", name, accountsType)
+		}
+		g.p("e := borsh.NewEncoder()")
+		g.p("e.Write(%s)", disc)
+		if len(ix.Args) > 0 {
+			g.p("if err := args.MarshalBorsh(e); err != nil {")
+			g.p("return solana.Instruction{}, err")
+			g.p("}")
+		}
+		g.p("metas := []solana.AccountMeta{")
+		for _, a := range accounts {
+			switch {
+			case a.Address != "":
+				g.p("{Pubkey: %q, IsSigner: %t, IsWritable: %t},", a.Address, a.Signer, a.Writable)
+			case a.Optional:
+				needOptional = true
+				g.p("optionalAccount(accounts.%s, %t, %t),", a.field, a.Signer, a.Writable)
+			default:
+				g.p("{Pubkey: accounts.%s, IsSigner: %t, IsWritable: %t},", a.field, a.Signer, a.Writable)
+			}
+		}
+		g.p("}")
+		g.p("return solana.Instruction{")
+		g.p("ProgramID: ProgramID,")
+		g.p("Accounts:  append(metas, accounts.RemainingAccounts...),")
+		g.p("Data:      e.Bytes(),")
+		g.p("}, nil")
+		g.p("}")
+	}
+
+	if needOptional {
+		g.p("")
+		g.p("// optionalAccount passes the program ID in place of an omitted optional")
+		g.p("// account, as Anchor expects.")
+		g.p("func optionalAccount(pubkey solana.Pubkey, signer, writable bool) solana.AccountMeta {")
+		g.p("if pubkey == \"\" {")
+		g.p("return solana.AccountMeta{Pubkey: ProgramID}")
+		g.p("}")
+		g.p("return solana.AccountMeta{Pubkey: pubkey, IsSigner: signer, IsWritable: writable}")
+		g.p("}")
+	}
+	return nil
+}
+
+func (g *generator) events() error {
+	if len(g.idl.Events) == 0 {
+		return nil
+	}
+	// Match longer discriminators first, in case one prefixes another.
+	events := append([]anchor.IDLEvent(nil), g.idl.Events...)
+	sort.SliceStable(events, func(i, j int) bool {
+		return len(events[i].Discriminator) > len(events[j].Discriminator)
+	})
+
+	for _, event := range g.idl.Events {
+		if _, ok := g.idl.TypeDef(event.Name); !ok {
+			return fmt.Errorf("anchorgen: event %s has no type", event.Name)
+		}
+		g.p("")
+		g.p("// %sEventDiscriminator prefixes %s events.", GoName(event.Name), GoName(event.Name))
+		g.p("var %sEventDiscriminator = %s", GoName(event.Name), byteSlice(event.Discriminator))
+	}
+
+	g.p("")
+	g.p("// DecodeEvent decodes an event the program emitted. The result is a")
+	g.p("// pointer to the event's type.")
+	g.p("func DecodeEvent(data []byte) (any, error) {")
+	g.p("switch {")
+	for _, event := range events {
+		name := GoName(event.Name)
+		g.p("case bytes.HasPrefix(data, %sEventDiscriminator):", name)
+		g.p("v := new(%s)", name)
+		g.p("if err := v.UnmarshalBorsh(borsh.NewDecoder(data[len(%sEventDiscriminator):])); err != nil {", name)
+		g.p("return nil, fmt.Errorf(\"%s: %s event: %%w\", err)", g.pkg, name)
+		g.p("}")
+		g.p("return v, nil")
+	}
+	g.p("}")
+	g.p("return nil, fmt.Errorf(\"%%w for event data\", anchor.ErrUnknownDiscriminator)")
+	g.p("}")
+	g.p("")
+	g.p("// DecodeEvents decodes the events the program emitted in a transaction's")
+	g.p("// log messages, in order. Data that is not one of its events is skipped.")
+	g.p("func DecodeEvents(logs []string) ([]any, error) {")
+	g.p("var events []any")
+	g.p("var firstErr error")
+	g.p("solana.ParseProgramLogs(logs).Walk(func(inv *solana.ProgramInvocation) {")
+	g.p("if inv.ProgramID != ProgramID {")
+	g.p("return")
+	g.p("}")
+	g.p("for _, data := range inv.Data {")
+	g.p("event, err := DecodeEvent(data)")
+	g.p("if errors.Is(err, anchor.ErrUnknownDiscriminator) {")
+	g.p("continue")
+	g.p("}")
+	g.p("if err != nil {")
+	g.p("if firstErr == nil {")
+	g.p("firstErr = err")
+	g.p("}")
+	g.p("continue")
+	g.p("}")
+	g.p("events = append(events, event)")
+	g.p("}")
+	g.p("})")
+	g.p("return events, firstErr")
+	g.p("}")
+	return nil
+}
+
+func (g *generator) errorCodes() error {
+	if len(g.idl.Errors) == 0 {
+		return nil
+	}
+	g.p("")
+	g.p("// ErrorCode is one of the program's custom errors.")
+	g.p("type ErrorCode uint32")
+	g.p("")
+	g.p("const (")
+	for _, e := range g.idl.Errors {
+		if e.Msg != "" {
+			g.p("// %s", e.Msg)
+		}
+		g.p("ErrorCode%s ErrorCode = %d", GoName(e.Name), e.Code)
+	}
+	g.p(")")
+	g.p("")
+	g.p("var errorNames = map[ErrorCode]string{")
+	for _, e := range g.idl.Errors {
+		g.p("ErrorCode%s: %q,", GoName(e.Name), e.Name)
+	}
+	g.p("}")
+	g.p("")
+	g.p("var errorMessages = map[ErrorCode]string{")
+	for _, e := range g.idl.Errors {
+		if e.Msg != "" {
+			g.p("ErrorCode%s: %q,", GoName(e.Name), e.Msg)
+		}
+	}
+	g.p("}")
+	g.p("")
+	g.p("func (c ErrorCode) String() string {")
+	g.p("if name, ok := errorNames[c]; ok {")
+	g.p("return name")
+	g.p("}")
+	g.p("return fmt.Sprintf(\"ErrorCode(%%d)\", uint32(c))")
+	g.p("}")
+	g.p("")
+	g.p("func (c ErrorCode) Error() string {")
+	g.p("if msg, ok := errorMessages[c]; ok {")
+	g.p("return fmt.Sprintf(\"%s: %%s: %%s\", c.String(), msg)", g.pkg)
+	g.p("}")
+	g.p("return \"%s: \" + c.String()", g.pkg)
+	g.p("}")
+	g.p("")
+	g.p("// ProgramError returns the program error a failed transaction reported,")
+	g.p("// if it failed with one. Programs the transaction invoked may use the same")
+	g.p("// codes, so check which instruction failed when that matters.")
+	g.p("func ProgramError(err *solana.TransactionError) (ErrorCode, bool) {")
+	g.p("if err == nil || err.Instruction == nil || err.Instruction.Kind != \"Custom\" {")
+	g.p("return 0, false")
+	g.p("}")
+	g.p("code := ErrorCode(err.Instruction.Custom)")
+	g.p("_, ok := errorNames[code]")
+	g.p("return code, ok")
+	g.p("}")
+	return nil
+}
+
+func byteSlice(b []byte) string {
+	parts := make([]string, len(b))
+	for i, v := range b {
+		parts[i] = fmt.Sprint(v)
+	}
+	return "[]byte{" + strings.Join(parts, ", ") + "}"
+}
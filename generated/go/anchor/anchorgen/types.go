@@ -0,0 +1,203 @@
+package anchorgen
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/solana-rpc/client/anchor"
+)
+
+// primitives maps IDL primitives to their Go type and the Encoder and
+// Decoder method that handles them.
+var primitives = map[string]struct{ goType, method string }{
+	"bool":   {"bool", "Bool"},
+	"u8":     {"uint8", "U8"},
+	"i8":     {"int8", "I8"},
+	"u16":    {"uint16", "U16"},
+	"i16":    {"int16", "I16"},
+	"u32":    {"uint32", "U32"},
+	"i32":    {"int32", "I32"},
+	"u64":    {"uint64", "U64"},
+	"i64":    {"int64", "I64"},
+	"u128":   {"borsh.Uint128", "U128"},
+	"i128":   {"borsh.Int128", "I128"},
+	"f32":    {"float32", "F32"},
+	"f64":    {"float64", "F64"},
+	"string": {"string", "String"},
+}
+
+func (g *generator) goType(t *anchor.IDLType) (string, error) {
+	if p, ok := primitives[t.Kind]; ok {
+		return p.goType, nil
+	}
+	switch t.Kind {
+	case "bytes":
+		return "[]byte", nil
+	case "pubkey":
+		return "solana.Pubkey", nil
+	case "option", "coption", "vec", "array":
+		elem, err := g.goType(t.Elem)
+		if err != nil {
+			return "", err
+		}
+		switch t.Kind {
+		case "vec":
+			return "[]" + elem, nil
+		case "array":
+			return fmt.Sprintf("[%d]%s", t.Len, elem), nil
+		}
+		return "*" + elem, nil
+	case "defined":
+		if _, ok := g.idl.TypeDef(t.Defined); !ok {
+			return "", fmt.Errorf("IDL has no type %s", t.Defined)
+		}
+		return GoName(t.Defined), nil
+	}
+	return "", fmt.Errorf("unsupported type %s", t)
+}
+
+// resolve follows defined types that are aliases, which have no methods of
+// their own, to the type they alias.
+func (g *generator) resolve(t *anchor.IDLType) *anchor.IDLType {
+	for depth := 0; t.Kind == "defined" && depth < 64; depth++ {
+		def, ok := g.idl.TypeDef(t.Defined)
+		if !ok || def.Kind != "alias" {
+			break
+		}
+		t = def.Alias
+	}
+	return t
+}
+
+// encode writes statements encoding expr, of type t, with the Encoder e.
+// goType has already accepted t.
+func (g *generator) encode(t *anchor.IDLType, expr string) {
+	t = g.resolve(t)
+	if p, ok := primitives[t.Kind]; ok {
+		g.p("e.%s(%s)", p.method, expr)
+		return
+	}
+	switch t.Kind {
+	case "bytes":
+		g.p("e.ByteString(%s)", expr)
+	case "pubkey":
+		g.p("if err := e.Pubkey(%s); err != nil {", expr)
+		g.p("return err")
+		g.p("}")
+	case "option", "coption":
+		method := "Option"
+		if t.Kind == "coption" {
+			method = "COption"
+		}
+		g.p("e.%s(%s != nil)", method, expr)
+		g.p("if %s != nil {", expr)
+		g.encode(t.Elem, "*"+expr)
+		g.p("}")
+	case "vec", "array":
+		if g.resolve(t.Elem).Kind == "u8" {
+			if t.Kind == "vec" {
+				g.p("e.ByteString(%s)", expr)
+			} else {
+				g.p("e.Write(%s[:])", operand(expr))
+			}
+			return
+		}
+		if t.Kind == "vec" {
+			g.p("e.Len(len(%s))", expr)
+		}
+		elem := g.temp("v")
+		g.p("for _, %s := range %s {", elem, expr)
+		g.encode(t.Elem, elem)
+		g.p("}")
+	case "defined":
+		g.p("if err := %s.MarshalBorsh(e); err != nil {", operand(expr))
+		g.p("return err")
+		g.p("}")
+	}
+}
+
+// decode writes statements decoding into target, an addressable expression
+// of type t, with the Decoder d. They assign to a declared err.
+func (g *generator) decode(t *anchor.IDLType, target string) error {
+	t = g.resolve(t)
+	if p, ok := primitives[t.Kind]; ok {
+		g.decodeCall(target, p.method)
+		return nil
+	}
+	switch t.Kind {
+	case "bytes":
+		g.decodeCall(target, "Bytes")
+	case "pubkey":
+		g.decodeCall(target, "Pubkey")
+	case "option", "coption":
+		method := "Option"
+		if t.Kind == "coption" {
+			method = "COption"
+		}
+		elemType, err := g.goType(t.Elem)
+		if err != nil {
+			return err
+		}
+		present := g.temp("ok")
+		g.p("var %s bool", present)
+		g.decodeCall(present, method)
+		g.p("if %s {", present)
+		g.p("%s = new(%s)", target, elemType)
+		if err := g.decode(t.Elem, "*"+target); err != nil {
+			return err
+		}
+		g.p("}")
+	case "vec", "array":
+		if g.resolve(t.Elem).Kind == "u8" {
+			if t.Kind == "vec" {
+				g.decodeCall(target, "Bytes")
+				return nil
+			}
+			raw := g.temp("b")
+			g.p("var %s []byte", raw)
+			g.p("if %s, err = d.Next(%d); err != nil {", raw, t.Len)
+			g.p("return err")
+			g.p("}")
+			g.p("copy(%s[:], %s)", operand(target), raw)
+			return nil
+		}
+		if t.Kind == "vec" {
+			elemType, err := g.goType(t.Elem)
+			if err != nil {
+				return err
+			}
+			n := g.temp("n")
+			g.p("var %s int", n)
+			g.decodeCall(n, "Len")
+			g.p("%s = make([]%s, %s)", target, elemType, n)
+		}
+		i := g.temp("i")
+		g.p("for %s := range %s {", i, target)
+		if err := g.decode(t.Elem, fmt.Sprintf("%s[%s]", operand(target), i)); err != nil {
+			return err
+		}
+		g.p("}")
+	case "defined":
+		g.p("if err = %s.UnmarshalBorsh(d); err != nil {", operand(target))
+		g.p("return err")
+		g.p("}")
+	default:
+		return fmt.Errorf("unsupported type %s", t)
+	}
+	return nil
+}
+
+func (g *generator) decodeCall(target, method string) {
+	g.p("if %s, err = d.%s(); err != nil {", target, method)
+	g.p("return err")
+	g.p("}")
+}
+
+// operand parenthesizes a dereference so it can be indexed, sliced or have
+// a method called on it.
+func operand(expr string) string {
+	if strings.HasPrefix(expr, "*") {
+		return "(" + expr + ")"
+	}
+	return expr
+}
@@ -0,0 +1,364 @@
+package anchor
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"reflect"
+
+	solana "github.com/solana-rpc/client"
+	"github.com/solana-rpc/client/borsh"
+)
+
+// ErrUnknownDiscriminator is matched by errors.Is when data starts with no
+// discriminator the IDL defines.
+var ErrUnknownDiscriminator = errors.New("anchor: unknown discriminator")
+
+// ErrAccountNotFound is matched by errors.Is when an account to decode does
+// not exist.
+var ErrAccountNotFound = errors.New("anchor: account not found")
+
+// Coder decodes a program's accounts, instructions and events using its IDL.
+//
+// Values decode into the Go types registered for them, or else into generic
+// values:
+//
+//   - bool, integers up to 64 bits and floats as the matching Go type
+//   - u128, i128, u256 and i256 as *big.Int
+//   - string as string, bytes and arrays or vectors of u8 as []byte
+//   - pubkey as solana.Pubkey
+//   - options as nil or the value
+//   - vectors and arrays as []any
+//   - structs as map[string]any by field name, tuple structs as []any
+//   - enums as the variant name for unit variants, and otherwise as a
+//     one-entry map from the variant name to its fields
+//
+// A Coder is safe for concurrent use once registration is done.
+type Coder struct {
+	idl        *IDL
+	registered map[string]reflect.Type
+}
+
+// NewCoder returns a Coder for idl.
+func NewCoder(idl *IDL) *Coder {
+	return &Coder{idl: idl, registered: make(map[string]reflect.Type)}
+}
+
+// IDL returns the coder's IDL.
+func (c *Coder) IDL() *IDL {
+	return c.idl
+}
+
+// RegisterAccount makes accounts named name decode into a new value of
+// prototype's type, which must be a struct or a pointer to one. Its fields
+// are decoded in order as described in package borsh, so public keys must be
+// [32]byte or strings tagged `borsh:"pubkey"`.
+func (c *Coder) RegisterAccount(name string, prototype any) error {
+	for _, a := range c.idl.Accounts {
+		if a.Name == name {
+			return c.register("account:"+name, prototype)
+		}
+	}
+	return fmt.Errorf("anchor: IDL has no account %s", name)
+}
+
+// RegisterEvent makes events named name decode into prototype's type, as
+// RegisterAccount does for accounts.
+func (c *Coder) RegisterEvent(name string, prototype any) error {
+	for _, e := range c.idl.Events {
+		if e.Name == name {
+			return c.register("event:"+name, prototype)
+		}
+	}
+	return fmt.Errorf("anchor: IDL has no event %s", name)
+}
+
+// RegisterInstruction makes the arguments of instructions named name decode
+// into prototype's type, a struct with one field per argument.
+func (c *Coder) RegisterInstruction(name string, prototype any) error {
+	for _, ix := range c.idl.Instructions {
+		if ix.Name == name {
+			return c.register("instruction:"+name, prototype)
+		}
+	}
+	return fmt.Errorf("anchor: IDL has no instruction %s", name)
+}
+
+func (c *Coder) register(key string, prototype any) error {
+	t := reflect.TypeOf(prototype)
+	if t != nil && t.Kind() == reflect.Pointer {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return fmt.Errorf("anchor: %s: prototype must be a struct or struct pointer, got %T", key, prototype)
+	}
+	c.registered[key] = t
+	return nil
+}
+
+// DecodedAccount is a decoded account. Value is a pointer to the registered
+// type, or a generic value.
+type DecodedAccount struct {
+	Name  string
+	Value any
+}
+
+// ProgramAccount is a decoded account of a program and its address, as
+// returned by the fetch helpers anchor-gen generates.
+type ProgramAccount[T any] struct {
+	Pubkey  solana.Pubkey
+	Account T
+}
+
+// DecodedEvent is a decoded event. Value is a pointer to the registered type,
+// or a generic value.
+type DecodedEvent struct {
+	Name  string
+	Value any
+}
+
+// DecodedInstruction is a decoded instruction.
+type DecodedInstruction struct {
+	Name string
+
+	// Index is the instruction's position in its message.
+	Index int
+
+	// Args is a pointer to the registered type, or a map from argument name
+	// to generic value.
+	Args any
+
+	// Accounts maps the IDL's account names to the accounts passed. Names
+	// in groups are joined with dots, as in "accounts.owner". Optional
+	// accounts that were left out are missing. Remaining holds accounts
+	// passed beyond those the IDL lists.
+	Accounts  map[string]solana.Pubkey
+	Remaining []solana.Pubkey
+}
+
+// DecodeAccount decodes account data. Bytes after the account's layout, as
+// in accounts allocated with spare room, are ignored.
+func (c *Coder) DecodeAccount(data []byte) (*DecodedAccount, error) {
+	var match *IDLAccount
+	for i, a := range c.idl.Accounts {
+		if bytes.HasPrefix(data, a.Discriminator) && (match == nil || len(a.Discriminator) > len(match.Discriminator)) {
+			match = &c.idl.Accounts[i]
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("%w for account data", ErrUnknownDiscriminator)
+	}
+	value, err := c.decodeNamed("account:"+match.Name, match.Name, data[len(match.Discriminator):])
+	if err != nil {
+		return nil, fmt.Errorf("anchor: account %s: %w", match.Name, err)
+	}
+	return &DecodedAccount{Name: match.Name, Value: value}, nil
+}
+
+// DecodeAccountInfo decodes an account fetched with a binary encoding. When
+// the IDL has the program's address, accounts owned by other programs are
+// rejected.
+func (c *Coder) DecodeAccountInfo(info *solana.AccountInfo) (*DecodedAccount, error) {
+	if info == nil || info.Owner == "" {
+		return nil, ErrAccountNotFound
+	}
+	if c.idl.Address != "" && info.Owner != c.idl.Address {
+		return nil, fmt.Errorf("anchor: account is owned by %s, not %s", info.Owner, c.idl.Address)
+	}
+	switch info.Data.Encoding {
+	case solana.EncodingBase58, solana.EncodingBase64:
+	default:
+		return nil, fmt.Errorf("anchor: account data has encoding %q; fetch it as base64", info.Data.Encoding)
+	}
+	return c.DecodeAccount(info.Data.Bytes)
+}
+
+// DecodeEvent decodes an event emitted with emit!, as found in
+// ProgramInvocation.Data.
+func (c *Coder) DecodeEvent(data []byte) (*DecodedEvent, error) {
+	var match *IDLEvent
+	for i, e := range c.idl.Events {
+		if bytes.HasPrefix(data, e.Discriminator) && (match == nil || len(e.Discriminator) > len(match.Discriminator)) {
+			match = &c.idl.Events[i]
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("%w for event data", ErrUnknownDiscriminator)
+	}
+	value, err := c.decodeNamed("event:"+match.Name, match.Name, data[len(match.Discriminator):])
+	if err != nil {
+		return nil, fmt.Errorf("anchor: event %s: %w", match.Name, err)
+	}
+	return &DecodedEvent{Name: match.Name, Value: value}, nil
+}
+
+// DecodeEvents decodes the events in a transaction's log messages, in the
+// order they were emitted. Only "Program data:" lines written by the IDL's
+// program are considered, or by any program if the IDL has no address;
+// lines that are not events of the IDL are skipped.
+func (c *Coder) DecodeEvents(logs []string) ([]DecodedEvent, error) {
+	var events []DecodedEvent
+	var firstErr error
+	solana.ParseProgramLogs(logs).Walk(func(inv *solana.ProgramInvocation) {
+		if c.idl.Address != "" && inv.ProgramID != c.idl.Address {
+			return
+		}
+		for _, data := range inv.Data {
+			event, err := c.DecodeEvent(data)
+			if errors.Is(err, ErrUnknownDiscriminator) {
+				continue
+			}
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				continue
+			}
+			events = append(events, *event)
+		}
+	})
+	return events, firstErr
+}
+
+// DecodeInstruction decodes an instruction's data into its name and
+// arguments. Accounts are left empty.
+func (c *Coder) DecodeInstruction(data []byte) (*DecodedInstruction, error) {
+	ix, err := c.matchInstruction(data)
+	if err != nil {
+		return nil, err
+	}
+	args, err := c.decodeArgs(ix, data[len(ix.Discriminator):])
+	if err != nil {
+		return nil, err
+	}
+	return &DecodedInstruction{Name: ix.Name, Args: args}, nil
+}
+
+// DecodeMessage decodes a message's instructions to the IDL's program,
+// skipping those to other programs. loaded holds the addresses the message
+// loads from lookup tables, from TransactionMeta.LoadedAddresses; it may be
+// nil for legacy messages.
+func (c *Coder) DecodeMessage(msg *solana.Message, loaded *solana.LoadedAddresses) ([]DecodedInstruction, error) {
+	if c.idl.Address == "" {
+		return nil, errors.New("anchor: IDL has no program address")
+	}
+	keys := msg.ResolvedAccountKeys(loaded)
+	var out []DecodedInstruction
+	for i, compiled := range msg.Instructions {
+		if int(compiled.ProgramIDIndex) >= len(keys) || keys[compiled.ProgramIDIndex] != c.idl.Address {
+			continue
+		}
+		accounts := make([]solana.Pubkey, len(compiled.Accounts))
+		for j, index := range compiled.Accounts {
+			if int(index) >= len(keys) {
+				return nil, fmt.Errorf("anchor: instruction %d: account index %d out of range; pass the loaded addresses", i, index)
+			}
+			accounts[j] = keys[index]
+		}
+
+		ix, err := c.matchInstruction(compiled.Data)
+		if err != nil {
+			return nil, fmt.Errorf("instruction %d: %w", i, err)
+		}
+		args, err := c.decodeArgs(ix, compiled.Data[len(ix.Discriminator):])
+		if err != nil {
+			return nil, fmt.Errorf("instruction %d: %w", i, err)
+		}
+		decoded := DecodedInstruction{Name: ix.Name, Index: i, Args: args, Accounts: make(map[string]solana.Pubkey)}
+		decoded.Remaining = c.nameAccounts(decoded.Accounts, "", ix.Accounts, accounts)
+		out = append(out, decoded)
+	}
+	return out, nil
+}
+
+// DecodeTransaction decodes the instructions to the IDL's program in a
+// transaction fetched with GetTransaction and a binary encoding.
+func (c *Coder) DecodeTransaction(resp *solana.TransactionResponse) ([]DecodedInstruction, error) {
+	if resp.Transaction == nil || resp.Transaction.Transaction == nil {
+		return nil, errors.New("anchor: transaction not decoded; fetch it with base64 encoding")
+	}
+	return c.DecodeMessage(&resp.Transaction.Transaction.Message, resp.Meta.LoadedAddresses)
+}
+
+// Error returns the IDL error with code.
+func (c *Coder) Error(code uint32) (IDLError, bool) {
+	for _, e := range c.idl.Errors {
+		if e.Code == code {
+			return e, true
+		}
+	}
+	return IDLError{}, false
+}
+
+// TransactionError returns the IDL error a failed transaction reported, if
+// it failed with one of the program's custom errors.
+func (c *Coder) TransactionError(err *solana.TransactionError) (IDLError, bool) {
+	if err == nil || err.Instruction == nil || err.Instruction.Kind != "Custom" {
+		return IDLError{}, false
+	}
+	return c.Error(err.Instruction.Custom)
+}
+
+func (c *Coder) matchInstruction(data []byte) (*IDLInstruction, error) {
+	var match *IDLInstruction
+	for i, ix := range c.idl.Instructions {
+		if bytes.HasPrefix(data, ix.Discriminator) && (match == nil || len(ix.Discriminator) > len(match.Discriminator)) {
+			match = &c.idl.Instructions[i]
+		}
+	}
+	if match == nil {
+		return nil, fmt.Errorf("%w for instruction data", ErrUnknownDiscriminator)
+	}
+	return match, nil
+}
+
+func (c *Coder) decodeArgs(ix *IDLInstruction, data []byte) (any, error) {
+	d := borsh.NewDecoder(data)
+	if t, ok := c.registered["instruction:"+ix.Name]; ok {
+		v := reflect.New(t)
+		if err := d.Decode(v.Interface()); err != nil {
+			return nil, fmt.Errorf("anchor: instruction %s: %w", ix.Name, err)
+		}
+		return v.Interface(), nil
+	}
+	args, err := c.decodeFields(d, ix.Args, 0)
+	if err != nil {
+		return nil, fmt.Errorf("anchor: instruction %s: %w", ix.Name, err)
+	}
+	return args, nil
+}
+
+// nameAccounts assigns accounts to the IDL's account names in order and
+// returns those left over.
+func (c *Coder) nameAccounts(out map[string]solana.Pubkey, prefix string, items []IDLInstructionAccount, accounts []solana.Pubkey) []solana.Pubkey {
+	for _, item := range items {
+		if len(item.Accounts) > 0 {
+			accounts = c.nameAccounts(out, prefix+item.Name+".", item.Accounts, accounts)
+			continue
+		}
+		if len(accounts) == 0 {
+			return nil
+		}
+		// Anchor passes the program ID in place of an omitted optional
+		// account.
+		if !item.Optional || accounts[0] != c.idl.Address {
+			out[prefix+item.Name] = accounts[0]
+		}
+		accounts = accounts[1:]
+	}
+	return accounts
+}
+
+// decodeNamed decodes the defined type name, into the type registered under
+// key if there is one.
+func (c *Coder) decodeNamed(key, name string, data []byte) (any, error) {
+	d := borsh.NewDecoder(data)
+	if t, ok := c.registered[key]; ok {
+		v := reflect.New(t)
+		if err := d.Decode(v.Interface()); err != nil {
+			return nil, err
+		}
+		return v.Interface(), nil
+	}
+	return c.decodeDefined(d, name, 0)
+}
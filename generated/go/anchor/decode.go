@@ -0,0 +1,176 @@
+package anchor
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/solana-rpc/client/borsh"
+)
+
+// maxTypeDepth bounds the nesting of defined types, so types that contain
+// themselves without consuming data cannot recurse forever.
+const maxTypeDepth = 64
+
+func (c *Coder) decodeDefined(d *borsh.Decoder, name string, depth int) (any, error) {
+	if depth > maxTypeDepth {
+		return nil, errors.New("types nested too deeply")
+	}
+	def, ok := c.idl.TypeDef(name)
+	if !ok {
+		return nil, fmt.Errorf("IDL has no type %s", name)
+	}
+	if def.Serialization != "borsh" {
+		return nil, fmt.Errorf("type %s uses %s serialization, which is not supported", name, def.Serialization)
+	}
+
+	switch def.Kind {
+	case "struct":
+		return c.decodeFields(d, def.Fields, depth+1)
+	case "enum":
+		index, err := d.U8()
+		if err != nil {
+			return nil, err
+		}
+		if int(index) >= len(def.Variants) {
+			return nil, fmt.Errorf("type %s has no variant %d", name, index)
+		}
+		variant := def.Variants[index]
+		if len(variant.Fields) == 0 {
+			return variant.Name, nil
+		}
+		fields, err := c.decodeFields(d, variant.Fields, depth+1)
+		if err != nil {
+			return nil, fmt.Errorf("%s::%s: %w", name, variant.Name, err)
+		}
+		return map[string]any{variant.Name: fields}, nil
+	case "alias":
+		return c.decodeType(d, def.Alias, depth+1)
+	}
+	return nil, fmt.Errorf("type %s has unsupported kind %q", name, def.Kind)
+}
+
+// decodeFields decodes named fields into a map and tuple fields into a slice.
+func (c *Coder) decodeFields(d *borsh.Decoder, fields []IDLField, depth int) (any, error) {
+	if len(fields) > 0 && fields[0].Name == "" {
+		tuple := make([]any, len(fields))
+		for i := range fields {
+			v, err := c.decodeType(d, &fields[i].Type, depth)
+			if err != nil {
+				return nil, fmt.Errorf("%d: %w", i, err)
+			}
+			tuple[i] = v
+		}
+		return tuple, nil
+	}
+
+	out := make(map[string]any, len(fields))
+	for i, f := range fields {
+		v, err := c.decodeType(d, &fields[i].Type, depth)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", f.Name, err)
+		}
+		out[f.Name] = v
+	}
+	return out, nil
+}
+
+func (c *Coder) decodeType(d *borsh.Decoder, t *IDLType, depth int) (any, error) {
+	switch t.Kind {
+	case "bool":
+		return d.Bool()
+	case "u8":
+		return d.U8()
+	case "i8":
+		return d.I8()
+	case "u16":
+		return d.U16()
+	case "i16":
+		return d.I16()
+	case "u32":
+		return d.U32()
+	case "i32":
+		return d.I32()
+	case "u64":
+		return d.U64()
+	case "i64":
+		return d.I64()
+	case "f32":
+		return d.F32()
+	case "f64":
+		return d.F64()
+	case "u128":
+		return decodeBigInt(d, 16, false)
+	case "i128":
+		return decodeBigInt(d, 16, true)
+	case "u256":
+		return decodeBigInt(d, 32, false)
+	case "i256":
+		return decodeBigInt(d, 32, true)
+	case "string":
+		return d.String()
+	case "bytes":
+		return d.Bytes()
+	case "pubkey":
+		return d.Pubkey()
+
+	case "option", "coption":
+		var present bool
+		var err error
+		if t.Kind == "coption" {
+			present, err = d.COption()
+		} else {
+			present, err = d.Option()
+		}
+		if err != nil || !present {
+			return nil, err
+		}
+		return c.decodeType(d, t.Elem, depth)
+
+	case "vec", "array":
+		n := t.Len
+		if t.Kind == "vec" {
+			var err error
+			if n, err = d.Len(); err != nil {
+				return nil, err
+			}
+		}
+		if t.Elem.Kind == "u8" {
+			b, err := d.Next(n)
+			if err != nil {
+				return nil, err
+			}
+			return append([]byte(nil), b...), nil
+		}
+		elems := make([]any, 0, n)
+		for i := 0; i < n; i++ {
+			v, err := c.decodeType(d, t.Elem, depth)
+			if err != nil {
+				return nil, fmt.Errorf("[%d]: %w", i, err)
+			}
+			elems = append(elems, v)
+		}
+		return elems, nil
+
+	case "defined":
+		return c.decodeDefined(d, t.Defined, depth)
+	}
+	return nil, fmt.Errorf("unsupported type %s", t)
+}
+
+// decodeBigInt decodes a little-endian integer of size bytes.
+func decodeBigInt(d *borsh.Decoder, size int, signed bool) (*big.Int, error) {
+	b, err := d.Next(size)
+	if err != nil {
+		return nil, err
+	}
+	be := make([]byte, size)
+	for i := range b {
+		be[size-1-i] = b[i]
+	}
+	n := new(big.Int).SetBytes(be)
+	if signed && be[0]&0x80 != 0 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), uint(8*size)))
+	}
+	return n, nil
+}
@@ -0,0 +1,490 @@
+// Package anchor decodes the accounts, instructions and events of Anchor
+// programs from their IDL.
+//
+//	idl, err := anchor.LoadIDLFile("target/idl/counter.json")
+//	if err != nil {
+//		return err
+//	}
+//	coder := anchor.NewCoder(idl)
+//	info, err := client.GetAccountInfo(ctx, address, &solana.GetAccountInfoConfig{Encoding: solana.EncodingBase64})
+//	if err != nil {
+//		return err
+//	}
+//	account, err := coder.DecodeAccountInfo(&info.Value)
+//
+// Both IDL formats are read: the legacy one, whose discriminators are
+// derived from names, and the one Anchor 0.30 introduced, which lists them.
+package anchor
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"unicode"
+
+	solana "github.com/solana-rpc/client"
+)
+
+// IDL is an Anchor program's interface, normalized from either IDL format.
+// Account and event layouts are in Types under the account or event name.
+type IDL struct {
+	// Address is the program ID. Legacy IDLs only have it when it was
+	// recorded in their metadata after deployment.
+	Address solana.Pubkey
+	Name    string
+	Version string
+
+	Instructions []IDLInstruction
+	Accounts     []IDLAccount
+	Events       []IDLEvent
+	Errors       []IDLError
+	Types        []IDLTypeDef
+}
+
+// IDLInstruction is an instruction handler.
+type IDLInstruction struct {
+	Name          string
+	Docs          []string
+	Discriminator []byte
+	Accounts      []IDLInstructionAccount
+	Args          []IDLField
+}
+
+// IDLInstructionAccount is an account an instruction takes, or a named group
+// of them when Accounts is set.
+type IDLInstructionAccount struct {
+	Name     string
+	Docs     []string
+	Writable bool
+	Signer   bool
+	Optional bool
+
+	// Address is set when the account is always the same one, such as the
+	// system program.
+	Address solana.Pubkey
+
+	Accounts []IDLInstructionAccount
+}
+
+// IDLAccount is an account type the program owns.
+type IDLAccount struct {
+	Name          string
+	Discriminator []byte
+}
+
+// IDLEvent is an event the program emits.
+type IDLEvent struct {
+	Name          string
+	Discriminator []byte
+}
+
+// IDLError is a program error, reported as a custom instruction error with
+// Code.
+type IDLError struct {
+	Code uint32 `json:"code"`
+	Name string `json:"name"`
+	Msg  string `json:"msg"`
+}
+
+// IDLTypeDef is a named type.
+type IDLTypeDef struct {
+	Name string
+	Docs []string
+
+	// Kind is "struct", "enum" or "alias".
+	Kind string
+
+	// Fields are a struct's fields; Variants an enum's variants. Alias is
+	// the aliased type.
+	Fields   []IDLField
+	Variants []IDLVariant
+	Alias    *IDLType
+
+	// Serialization is "borsh" unless the type is zero-copy, in which case
+	// it has C layout and cannot be decoded by this package.
+	Serialization string
+}
+
+// IDLField is a struct field, instruction argument or enum variant field.
+// Fields of tuple structs and tuple variants have no name.
+type IDLField struct {
+	Name string
+	Docs []string
+	Type IDLType
+}
+
+// IDLVariant is an enum variant. Unit variants have no fields.
+type IDLVariant struct {
+	Name   string
+	Fields []IDLField
+}
+
+// IDLType is a field type.
+type IDLType struct {
+	// Kind is a primitive such as "u64", "string", "bytes" or "pubkey", or
+	// one of "option", "coption", "vec", "array" and "defined".
+	Kind string
+
+	// Elem is the element type of options, vectors and arrays, and Len an
+	// array's length.
+	Elem *IDLType
+	Len  int
+
+	// Defined names the type in IDL.Types a "defined" type refers to.
+	Defined string
+}
+
+func (t IDLType) String() string {
+	switch t.Kind {
+	case "option", "coption", "vec":
+		return fmt.Sprintf("%s<%s>", t.Kind, t.Elem)
+	case "array":
+		return fmt.Sprintf("[%s; %d]", t.Elem, t.Len)
+	case "defined":
+		return t.Defined
+	}
+	return t.Kind
+}
+
+func (t *IDLType) UnmarshalJSON(data []byte) error {
+	var primitive string
+	if err := json.Unmarshal(data, &primitive); err == nil {
+		if primitive == "publicKey" {
+			primitive = "pubkey"
+		}
+		*t = IDLType{Kind: primitive}
+		return nil
+	}
+
+	var obj map[string]json.RawMessage
+	if err := json.Unmarshal(data, &obj); err != nil || len(obj) != 1 {
+		return fmt.Errorf("anchor: unsupported IDL type %s", data)
+	}
+	for kind, value := range obj {
+		switch kind {
+		case "option", "coption", "vec":
+			elem := new(IDLType)
+			if err := json.Unmarshal(value, elem); err != nil {
+				return err
+			}
+			*t = IDLType{Kind: kind, Elem: elem}
+		case "array":
+			var pair []json.RawMessage
+			if err := json.Unmarshal(value, &pair); err != nil || len(pair) != 2 {
+				return fmt.Errorf("anchor: malformed array type %s", value)
+			}
+			elem := new(IDLType)
+			if err := json.Unmarshal(pair[0], elem); err != nil {
+				return err
+			}
+			var n int
+			if err := json.Unmarshal(pair[1], &n); err != nil {
+				return fmt.Errorf("anchor: array length %s is not a number; generics are not supported", pair[1])
+			}
+			*t = IDLType{Kind: kind, Elem: elem, Len: n}
+		case "defined":
+			// Legacy IDLs give the name; newer ones an object that may
+			// also carry generic arguments.
+			var name string
+			if err := json.Unmarshal(value, &name); err != nil {
+				var ref struct {
+					Name     string            `json:"name"`
+					Generics []json.RawMessage `json:"generics"`
+				}
+				if err := json.Unmarshal(value, &ref); err != nil {
+					return fmt.Errorf("anchor: malformed defined type %s", value)
+				}
+				if len(ref.Generics) > 0 {
+					return fmt.Errorf("anchor: generic type %s is not supported", ref.Name)
+				}
+				name = ref.Name
+			}
+			*t = IDLType{Kind: kind, Defined: name}
+		default:
+			return fmt.Errorf("anchor: unsupported IDL type %s", data)
+		}
+	}
+	return nil
+}
+
+// LoadIDLFile reads and parses an IDL file.
+func LoadIDLFile(path string) (*IDL, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return ParseIDL(data)
+}
+
+// ParseIDL parses an IDL in either format. Legacy IDLs get the
+// discriminators Anchor derives from names.
+func ParseIDL(data []byte) (*IDL, error) {
+	var raw rawIDL
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("anchor: parse IDL: %w", err)
+	}
+	legacy := raw.Address == "" && raw.Metadata.Spec == ""
+
+	idl := &IDL{
+		Address: raw.Address,
+		Name:    raw.Metadata.Name,
+		Version: raw.Metadata.Version,
+		Errors:  raw.Errors,
+	}
+	if legacy {
+		idl.Address, idl.Name, idl.Version = raw.Metadata.Address, raw.Name, raw.Version
+	}
+
+	for _, rt := range raw.Types {
+		def, err := rt.Type.typeDef(rt.Name)
+		if err != nil {
+			return nil, err
+		}
+		def.Docs, def.Serialization = rt.Docs, rt.Serialization
+		if def.Serialization == "" {
+			def.Serialization = "borsh"
+		}
+		idl.Types = append(idl.Types, def)
+	}
+
+	for _, ri := range raw.Instructions {
+		ix := IDLInstruction{Name: ri.Name, Docs: ri.Docs, Discriminator: ri.Discriminator}
+		if legacy {
+			ix.Discriminator = Discriminator("global", SnakeCase(ri.Name))
+		}
+		ix.Accounts = instructionAccounts(ri.Accounts)
+		for _, arg := range ri.Args {
+			ix.Args = append(ix.Args, IDLField(arg))
+		}
+		idl.Instructions = append(idl.Instructions, ix)
+	}
+
+	for _, ra := range raw.Accounts {
+		account := IDLAccount{Name: ra.Name, Discriminator: ra.Discriminator}
+		if legacy {
+			account.Discriminator = Discriminator("account", ra.Name)
+			// Legacy IDLs define account layouts inline.
+			if ra.Type != nil {
+				def, err := ra.Type.typeDef(ra.Name)
+				if err != nil {
+					return nil, err
+				}
+				def.Serialization = "borsh"
+				idl.Types = append(idl.Types, def)
+			}
+		}
+		idl.Accounts = append(idl.Accounts, account)
+	}
+
+	for _, re := range raw.Events {
+		event := IDLEvent{Name: re.Name, Discriminator: re.Discriminator}
+		if legacy {
+			event.Discriminator = Discriminator("event", re.Name)
+			def := IDLTypeDef{Name: re.Name, Kind: "struct", Serialization: "borsh"}
+			for _, f := range re.Fields {
+				def.Fields = append(def.Fields, IDLField(f))
+			}
+			idl.Types = append(idl.Types, def)
+		}
+		idl.Events = append(idl.Events, event)
+	}
+
+	for _, ix := range idl.Instructions {
+		if len(ix.Discriminator) == 0 {
+			return nil, fmt.Errorf("anchor: instruction %s has no discriminator", ix.Name)
+		}
+	}
+	for _, a := range idl.Accounts {
+		if len(a.Discriminator) == 0 {
+			return nil, fmt.Errorf("anchor: account %s has no discriminator", a.Name)
+		}
+	}
+	for _, e := range idl.Events {
+		if len(e.Discriminator) == 0 {
+			return nil, fmt.Errorf("anchor: event %s has no discriminator", e.Name)
+		}
+	}
+	return idl, nil
+}
+
+// TypeDef returns the type named name.
+func (idl *IDL) TypeDef(name string) (*IDLTypeDef, bool) {
+	for i := range idl.Types {
+		if idl.Types[i].Name == name {
+			return &idl.Types[i], true
+		}
+	}
+	return nil, false
+}
+
+// Discriminator returns the discriminator Anchor derives for a name in a
+// namespace: the first 8 bytes of sha256("namespace:name"). Namespaces are
+// "global" for instructions, whose names are snake_case, "account" and
+// "event".
+func Discriminator(namespace, name string) []byte {
+	sum := sha256.Sum256([]byte(namespace + ":" + name))
+	return sum[:8]
+}
+
+// SnakeCase converts a camelCase or PascalCase name to snake_case the way
+// Anchor does when deriving instruction discriminators.
+func SnakeCase(name string) string {
+	runes := []rune(name)
+	var b strings.Builder
+	for i, r := range runes {
+		if unicode.IsUpper(r) {
+			if i > 0 && (unicode.IsLower(runes[i-1]) || unicode.IsDigit(runes[i-1]) ||
+				unicode.IsUpper(runes[i-1]) && i+1 < len(runes) && unicode.IsLower(runes[i+1])) {
+				b.WriteByte('_')
+			}
+			r = unicode.ToLower(r)
+		}
+		b.WriteRune(r)
+	}
+	return b.String()
+}
+
+type rawIDL struct {
+	Address  string `json:"address"`
+	Name     string `json:"name"`
+	Version  string `json:"version"`
+	Metadata struct {
+		Name    string `json:"name"`
+		Version string `json:"version"`
+		Spec    string `json:"spec"`
+		Address string `json:"address"`
+	} `json:"metadata"`
+	Instructions []struct {
+		Name          string                  `json:"name"`
+		Docs          []string                `json:"docs"`
+		Discriminator []byte                  `json:"discriminator"`
+		Accounts      []rawInstructionAccount `json:"accounts"`
+		Args          []rawField              `json:"args"`
+	} `json:"instructions"`
+	Accounts []struct {
+		Name          string      `json:"name"`
+		Discriminator []byte      `json:"discriminator"`
+		Type          *rawTypeDef `json:"type"`
+	} `json:"accounts"`
+	Events []struct {
+		Name          string     `json:"name"`
+		Discriminator []byte     `json:"discriminator"`
+		Fields        []rawField `json:"fields"`
+	} `json:"events"`
+	Errors []IDLError `json:"errors"`
+	Types  []struct {
+		Name          string     `json:"name"`
+		Docs          []string   `json:"docs"`
+		Serialization string     `json:"serialization"`
+		Type          rawTypeDef `json:"type"`
+	} `json:"types"`
+}
+
+// rawInstructionAccount reads both the legacy isMut/isSigner/isOptional flags
+// and their newer names.
+type rawInstructionAccount struct {
+	Name       string                  `json:"name"`
+	Docs       []string                `json:"docs"`
+	Writable   bool                    `json:"writable"`
+	Signer     bool                    `json:"signer"`
+	Optional   bool                    `json:"optional"`
+	IsMut      bool                    `json:"isMut"`
+	IsSigner   bool                    `json:"isSigner"`
+	IsOptional bool                    `json:"isOptional"`
+	Address    string                  `json:"address"`
+	Accounts   []rawInstructionAccount `json:"accounts"`
+}
+
+func instructionAccounts(raw []rawInstructionAccount) []IDLInstructionAccount {
+	var out []IDLInstructionAccount
+	for _, ra := range raw {
+		out = append(out, IDLInstructionAccount{
+			Name:     ra.Name,
+			Docs:     ra.Docs,
+			Writable: ra.Writable || ra.IsMut,
+			Signer:   ra.Signer || ra.IsSigner,
+			Optional: ra.Optional || ra.IsOptional,
+			Address:  ra.Address,
+			Accounts: instructionAccounts(ra.Accounts),
+		})
+	}
+	return out
+}
+
+type rawField struct {
+	Name string   `json:"name"`
+	Docs []string `json:"docs"`
+	Type IDLType  `json:"type"`
+}
+
+type rawTypeDef struct {
+	Kind     string          `json:"kind"`
+	Fields   json.RawMessage `json:"fields"`
+	Variants []struct {
+		Name   string          `json:"name"`
+		Fields json.RawMessage `json:"fields"`
+	} `json:"variants"`
+	// Alias is the newer format's aliased type, Value the legacy one's.
+	Alias *IDLType `json:"alias"`
+	Value *IDLType `json:"value"`
+}
+
+func (r *rawTypeDef) typeDef(name string) (IDLTypeDef, error) {
+	def := IDLTypeDef{Name: name, Kind: r.Kind}
+	var err error
+	switch r.Kind {
+	case "struct":
+		def.Fields, err = parseFields(r.Fields)
+	case "enum":
+		for _, v := range r.Variants {
+			variant := IDLVariant{Name: v.Name}
+			if variant.Fields, err = parseFields(v.Fields); err != nil {
+				break
+			}
+			def.Variants = append(def.Variants, variant)
+		}
+	case "type", "alias":
+		def.Kind, def.Alias = "alias", r.Alias
+		if def.Alias == nil {
+			def.Alias = r.Value
+		}
+		if def.Alias == nil {
+			err = fmt.Errorf("no aliased type")
+		}
+	default:
+		err = fmt.Errorf("unsupported kind %q", r.Kind)
+	}
+	if err != nil {
+		return IDLTypeDef{}, fmt.Errorf("anchor: type %s: %w", name, err)
+	}
+	return def, nil
+}
+
+// parseFields reads named fields, or the bare types of tuple fields.
+func parseFields(data json.RawMessage) ([]IDLField, error) {
+	if len(data) == 0 || string(data) == "null" {
+		return nil, nil
+	}
+	var items []json.RawMessage
+	if err := json.Unmarshal(data, &items); err != nil {
+		return nil, err
+	}
+	fields := make([]IDLField, len(items))
+	for i, item := range items {
+		var named struct {
+			Name *string         `json:"name"`
+			Docs []string        `json:"docs"`
+			Type json.RawMessage `json:"type"`
+		}
+		if err := json.Unmarshal(item, &named); err == nil && named.Name != nil && named.Type != nil {
+			fields[i].Name, fields[i].Docs = *named.Name, named.Docs
+			item = named.Type
+		}
+		if err := json.Unmarshal(item, &fields[i].Type); err != nil {
+			return nil, err
+		}
+	}
+	return fields, nil
+}
@@ -0,0 +1,146 @@
+package solana
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// DefaultBlockhashRefreshInterval is how often a BlockhashProvider fetches a
+// new blockhash when its config sets no interval.
+const DefaultBlockhashRefreshInterval = 5 * time.Second
+
+// BlockhashProviderConfig configures NewBlockhashProvider. The zero value
+// refreshes every DefaultBlockhashRefreshInterval at the client's commitment.
+type BlockhashProviderConfig struct {
+	// Commitment is the commitment blockhashes are fetched at.
+	Commitment Commitment
+
+	// RefreshInterval is the time between background refreshes. It also
+	// bounds each background fetch, so a hung request cannot stall them.
+	RefreshInterval time.Duration
+
+	// MaxAge is how old the cached blockhash may be before Current fetches
+	// one itself. The default is four refresh intervals, so a few failed
+	// refreshes are tolerated without callers noticing.
+	MaxAge time.Duration
+}
+
+// BlockhashProvider keeps a recent blockhash fetched in the background, so
+// transactions can be built without a round trip each. Close stops it.
+type BlockhashProvider struct {
+	client RPC
+	config BlockhashProviderConfig
+
+	// fetching holds a token while a fetch is in flight, so concurrent
+	// stale reads share one and waiters can give up when their context ends.
+	fetching chan struct{}
+
+	mu        sync.RWMutex
+	current   LatestBlockhash
+	fetchedAt time.Time
+
+	cancel context.CancelFunc
+	done   chan struct{}
+}
+
+// NewBlockhashProvider starts refreshing blockhashes from c, typically a
+// Client or Session, in the background. The first blockhash is fetched on the
+// first refresh or the first call to Current, whichever comes sooner. config
+// may be nil.
+func NewBlockhashProvider(c RPC, config *BlockhashProviderConfig) *BlockhashProvider {
+	p := &BlockhashProvider{
+		client:   c,
+		fetching: make(chan struct{}, 1),
+		done:     make(chan struct{}),
+	}
+	if config != nil {
+		p.config = *config
+	}
+	if p.config.RefreshInterval <= 0 {
+		p.config.RefreshInterval = DefaultBlockhashRefreshInterval
+	}
+	if p.config.MaxAge <= 0 {
+		p.config.MaxAge = 4 * p.config.RefreshInterval
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	p.cancel = cancel
+	go p.run(ctx)
+	return p
+}
+
+// Current returns the cached blockhash and the last block height at which
+// transactions using it are valid. It only goes to the network when the
+// cached value is older than MaxAge or none has been fetched yet, sharing a
+// fetch already in flight; it stops waiting for that fetch when ctx ends.
+func (p *BlockhashProvider) Current(ctx context.Context) (LatestBlockhash, error) {
+	if bh, ok := p.fresh(time.Now()); ok {
+		return bh, nil
+	}
+	return p.fetch(ctx)
+}
+
+// Close stops the background refresh and waits for it to exit.
+func (p *BlockhashProvider) Close() {
+	p.cancel()
+	<-p.done
+}
+
+func (p *BlockhashProvider) run(ctx context.Context) {
+	defer close(p.done)
+
+	ticker := time.NewTicker(p.config.RefreshInterval)
+	defer ticker.Stop()
+	for {
+		// Failures are not reported here: the cached value ages out and
+		// Current surfaces the error from its own fetch.
+		fetchCtx, cancel := context.WithTimeout(ctx, p.config.RefreshInterval)
+		p.fetch(fetchCtx)
+		cancel()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (p *BlockhashProvider) fresh(now time.Time) (LatestBlockhash, bool) {
+	p.mu.RLock()
+	defer p.mu.RUnlock()
+	if p.fetchedAt.IsZero() || now.Sub(p.fetchedAt) > p.config.MaxAge {
+		return LatestBlockhash{}, false
+	}
+	return p.current, true
+}
+
+func (p *BlockhashProvider) fetch(ctx context.Context) (LatestBlockhash, error) {
+	start := time.Now()
+	select {
+	case p.fetching <- struct{}{}:
+	case <-ctx.Done():
+		return LatestBlockhash{}, fmt.Errorf("fetch blockhash: %w", ctx.Err())
+	}
+	defer func() { <-p.fetching }()
+
+	// Another caller may have fetched while this one waited for the lock.
+	p.mu.RLock()
+	current, fetchedAt := p.current, p.fetchedAt
+	p.mu.RUnlock()
+	if fetchedAt.After(start) {
+		return current, nil
+	}
+
+	resp, err := p.client.GetLatestBlockhash(ctx, &CommitmentConfig{Commitment: p.config.Commitment})
+	if err != nil {
+		return LatestBlockhash{}, fmt.Errorf("fetch blockhash: %w", err)
+	}
+
+	p.mu.Lock()
+	p.current, p.fetchedAt = resp.Value, time.Now()
+	p.mu.Unlock()
+	return resp.Value, nil
+}
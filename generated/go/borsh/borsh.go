@@ -0,0 +1,337 @@
+// Package borsh implements the Borsh binary format used by Solana programs,
+// and by Anchor in particular, for account data and instruction arguments.
+//
+// Encoder and Decoder read and write individual values, for code that knows
+// its layout, such as generated bindings. Marshal and Unmarshal map Go
+// values to Borsh by reflection:
+//
+//   - bool, integers and floats are fixed-size little-endian values
+//   - strings, []byte and other slices are u32-length-prefixed
+//   - arrays, including [32]byte public keys, are written without a length
+//   - pointers are options: a 0 or 1 byte, then the value if 1
+//   - structs are their exported fields in order, skipping fields tagged
+//     `borsh:"-"`; a string field tagged `borsh:"pubkey"` is a base58
+//     public key stored as 32 bytes
+//   - Uint128 and Int128 are 16-byte little-endian integers
+//
+// Types implementing Marshaler or Unmarshaler, such as enums with data,
+// encode themselves.
+package borsh
+
+import (
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+
+	solana "github.com/solana-rpc/client"
+)
+
+// ErrUnexpectedEOF is returned when data ends before a value is complete.
+var ErrUnexpectedEOF = errors.New("borsh: unexpected end of data")
+
+// Decoder reads Borsh values from a byte slice.
+type Decoder struct {
+	data []byte
+	off  int
+}
+
+// NewDecoder returns a decoder reading data from the start.
+func NewDecoder(data []byte) *Decoder {
+	return &Decoder{data: data}
+}
+
+// Remaining returns the number of bytes not yet read.
+func (d *Decoder) Remaining() int {
+	return len(d.data) - d.off
+}
+
+// Next returns the next n bytes. The slice aliases the decoder's data.
+func (d *Decoder) Next(n int) ([]byte, error) {
+	if n < 0 || n > d.Remaining() {
+		return nil, ErrUnexpectedEOF
+	}
+	d.off += n
+	return d.data[d.off-n : d.off], nil
+}
+
+func (d *Decoder) Bool() (bool, error) {
+	b, err := d.U8()
+	if err != nil {
+		return false, err
+	}
+	switch b {
+	case 0:
+		return false, nil
+	case 1:
+		return true, nil
+	}
+	return false, fmt.Errorf("borsh: invalid bool %d", b)
+}
+
+func (d *Decoder) U8() (uint8, error) {
+	b, err := d.Next(1)
+	if err != nil {
+		return 0, err
+	}
+	return b[0], nil
+}
+
+func (d *Decoder) U16() (uint16, error) {
+	b, err := d.Next(2)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint16(b), nil
+}
+
+func (d *Decoder) U32() (uint32, error) {
+	b, err := d.Next(4)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint32(b), nil
+}
+
+func (d *Decoder) U64() (uint64, error) {
+	b, err := d.Next(8)
+	if err != nil {
+		return 0, err
+	}
+	return binary.LittleEndian.Uint64(b), nil
+}
+
+func (d *Decoder) U128() (Uint128, error) {
+	lo, err := d.U64()
+	if err != nil {
+		return Uint128{}, err
+	}
+	hi, err := d.U64()
+	return Uint128{Lo: lo, Hi: hi}, err
+}
+
+func (d *Decoder) I8() (int8, error) {
+	v, err := d.U8()
+	return int8(v), err
+}
+
+func (d *Decoder) I16() (int16, error) {
+	v, err := d.U16()
+	return int16(v), err
+}
+
+func (d *Decoder) I32() (int32, error) {
+	v, err := d.U32()
+	return int32(v), err
+}
+
+func (d *Decoder) I64() (int64, error) {
+	v, err := d.U64()
+	return int64(v), err
+}
+
+func (d *Decoder) I128() (Int128, error) {
+	v, err := d.U128()
+	return Int128(v), err
+}
+
+func (d *Decoder) F32() (float32, error) {
+	v, err := d.U32()
+	return math.Float32frombits(v), err
+}
+
+func (d *Decoder) F64() (float64, error) {
+	v, err := d.U64()
+	return math.Float64frombits(v), err
+}
+
+// Len reads a u32 collection length and checks that at least that many
+// bytes remain, so corrupt lengths fail before anything is allocated.
+func (d *Decoder) Len() (int, error) {
+	n, err := d.U32()
+	if err != nil {
+		return 0, err
+	}
+	if int64(n) > int64(d.Remaining()) {
+		return 0, fmt.Errorf("borsh: length %d exceeds the %d bytes left", n, d.Remaining())
+	}
+	return int(n), nil
+}
+
+// Bytes reads a length-prefixed byte string. The result is a copy.
+func (d *Decoder) Bytes() ([]byte, error) {
+	n, err := d.Len()
+	if err != nil {
+		return nil, err
+	}
+	b, err := d.Next(n)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), b...), nil
+}
+
+func (d *Decoder) String() (string, error) {
+	b, err := d.Bytes()
+	return string(b), err
+}
+
+// Pubkey reads a 32-byte public key and returns it base58-encoded.
+func (d *Decoder) Pubkey() (solana.Pubkey, error) {
+	b, err := d.Next(32)
+	if err != nil {
+		return "", err
+	}
+	return solana.EncodeBase58(b), nil
+}
+
+// Option reads an option tag and reports whether a value follows.
+func (d *Decoder) Option() (bool, error) {
+	tag, err := d.U8()
+	if err != nil {
+		return false, err
+	}
+	switch tag {
+	case 0:
+		return false, nil
+	case 1:
+		return true, nil
+	}
+	return false, fmt.Errorf("borsh: invalid option tag %d", tag)
+}
+
+// COption reads the 4-byte tag of an SPL COption and reports whether a value
+// follows.
+func (d *Decoder) COption() (bool, error) {
+	tag, err := d.U32()
+	if err != nil {
+		return false, err
+	}
+	switch tag {
+	case 0:
+		return false, nil
+	case 1:
+		return true, nil
+	}
+	return false, fmt.Errorf("borsh: invalid coption tag %d", tag)
+}
+
+// Encoder appends Borsh values to a byte slice.
+type Encoder struct {
+	buf []byte
+}
+
+// NewEncoder returns an empty encoder.
+func NewEncoder() *Encoder {
+	return &Encoder{}
+}
+
+// Bytes returns the encoded data. The slice aliases the encoder's buffer.
+func (e *Encoder) Bytes() []byte {
+	return e.buf
+}
+
+// Write appends b unprefixed, for fixed-size fields and discriminators.
+func (e *Encoder) Write(b []byte) {
+	e.buf = append(e.buf, b...)
+}
+
+func (e *Encoder) Bool(v bool) {
+	if v {
+		e.U8(1)
+	} else {
+		e.U8(0)
+	}
+}
+
+func (e *Encoder) U8(v uint8)   { e.buf = append(e.buf, v) }
+func (e *Encoder) U16(v uint16) { e.buf = binary.LittleEndian.AppendUint16(e.buf, v) }
+func (e *Encoder) U32(v uint32) { e.buf = binary.LittleEndian.AppendUint32(e.buf, v) }
+func (e *Encoder) U64(v uint64) { e.buf = binary.LittleEndian.AppendUint64(e.buf, v) }
+
+func (e *Encoder) U128(v Uint128) {
+	e.U64(v.Lo)
+	e.U64(v.Hi)
+}
+
+func (e *Encoder) I8(v int8)     { e.U8(uint8(v)) }
+func (e *Encoder) I16(v int16)   { e.U16(uint16(v)) }
+func (e *Encoder) I32(v int32)   { e.U32(uint32(v)) }
+func (e *Encoder) I64(v int64)   { e.U64(uint64(v)) }
+func (e *Encoder) I128(v Int128) { e.U128(Uint128(v)) }
+
+func (e *Encoder) F32(v float32) { e.U32(math.Float32bits(v)) }
+func (e *Encoder) F64(v float64) { e.U64(math.Float64bits(v)) }
+
+// Len writes a u32 collection length.
+func (e *Encoder) Len(n int) {
+	e.U32(uint32(n))
+}
+
+// ByteString writes a length-prefixed byte string.
+func (e *Encoder) ByteString(b []byte) {
+	e.Len(len(b))
+	e.Write(b)
+}
+
+func (e *Encoder) String(s string) {
+	e.Len(len(s))
+	e.buf = append(e.buf, s...)
+}
+
+// Pubkey writes a base58 public key as its 32 bytes.
+func (e *Encoder) Pubkey(pk solana.Pubkey) error {
+	b, err := solana.DecodeBase58(pk)
+	if err != nil {
+		return fmt.Errorf("borsh: pubkey: %w", err)
+	}
+	if len(b) != 32 {
+		return fmt.Errorf("borsh: pubkey %q is %d bytes, want 32", pk, len(b))
+	}
+	e.Write(b)
+	return nil
+}
+
+// Option writes an option tag.
+func (e *Encoder) Option(present bool) {
+	e.Bool(present)
+}
+
+// COption writes the 4-byte tag of an SPL COption.
+func (e *Encoder) COption(present bool) {
+	if present {
+		e.U32(1)
+	} else {
+		e.U32(0)
+	}
+}
+
+// Uint128 is an unsigned 128-bit integer.
+type Uint128 struct {
+	Lo, Hi uint64
+}
+
+// Int128 is a signed 128-bit integer in two's complement.
+type Int128 struct {
+	Lo, Hi uint64
+}
+
+// BigInt returns v as a big.Int.
+func (v Uint128) BigInt() *big.Int {
+	n := new(big.Int).SetUint64(v.Hi)
+	n.Lsh(n, 64)
+	return n.Or(n, new(big.Int).SetUint64(v.Lo))
+}
+
+// BigInt returns v as a big.Int.
+func (v Int128) BigInt() *big.Int {
+	n := Uint128(v).BigInt()
+	if v.Hi>>63 == 1 {
+		n.Sub(n, new(big.Int).Lsh(big.NewInt(1), 128))
+	}
+	return n
+}
+
+func (v Uint128) String() string { return v.BigInt().String() }
+func (v Int128) String() string  { return v.BigInt().String() }
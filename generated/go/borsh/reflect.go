@@ -0,0 +1,252 @@
+package borsh
+
+import (
+	"fmt"
+	"reflect"
+)
+
+// Marshaler is implemented by types that encode themselves.
+type Marshaler interface {
+	MarshalBorsh(e *Encoder) error
+}
+
+// Unmarshaler is implemented by types that decode themselves.
+type Unmarshaler interface {
+	UnmarshalBorsh(d *Decoder) error
+}
+
+var (
+	marshalerType   = reflect.TypeOf((*Marshaler)(nil)).Elem()
+	unmarshalerType = reflect.TypeOf((*Unmarshaler)(nil)).Elem()
+)
+
+// Marshal returns the Borsh encoding of v.
+func Marshal(v any) ([]byte, error) {
+	e := NewEncoder()
+	if err := e.Encode(v); err != nil {
+		return nil, err
+	}
+	return e.Bytes(), nil
+}
+
+// Unmarshal decodes data into the value v points to. Bytes left over after
+// the value are an error.
+func Unmarshal(data []byte, v any) error {
+	d := NewDecoder(data)
+	if err := d.Decode(v); err != nil {
+		return err
+	}
+	if d.Remaining() != 0 {
+		return fmt.Errorf("borsh: %d trailing bytes", d.Remaining())
+	}
+	return nil
+}
+
+// Encode appends the encoding of v. A pointer passed to Encode is followed
+// rather than encoded as an option.
+func (e *Encoder) Encode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() == reflect.Pointer {
+		if rv.IsNil() {
+			return fmt.Errorf("borsh: Encode got a nil %T", v)
+		}
+		rv = rv.Elem()
+	}
+	return e.encodeValue(rv, "")
+}
+
+// Decode reads a value into the value v points to.
+func (d *Decoder) Decode(v any) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Pointer || rv.IsNil() {
+		return fmt.Errorf("borsh: Decode needs a non-nil pointer, got %T", v)
+	}
+	return d.decodeValue(rv.Elem(), "")
+}
+
+func (e *Encoder) encodeValue(v reflect.Value, tag string) error {
+	// Pointers below the top level are options, even to types that marshal
+	// themselves, so check for them before looking for a Marshaler.
+	if v.Kind() == reflect.Pointer {
+		e.Option(!v.IsNil())
+		if v.IsNil() {
+			return nil
+		}
+		return e.encodeValue(v.Elem(), tag)
+	}
+	if v.Type().Implements(marshalerType) {
+		return v.Interface().(Marshaler).MarshalBorsh(e)
+	}
+	if reflect.PointerTo(v.Type()).Implements(marshalerType) {
+		if !v.CanAddr() {
+			// Copy the value so its pointer-receiver method can be called.
+			p := reflect.New(v.Type())
+			p.Elem().Set(v)
+			v = p.Elem()
+		}
+		return v.Addr().Interface().(Marshaler).MarshalBorsh(e)
+	}
+
+	switch v.Kind() {
+	case reflect.Bool:
+		e.Bool(v.Bool())
+	case reflect.Uint8:
+		e.U8(uint8(v.Uint()))
+	case reflect.Uint16:
+		e.U16(uint16(v.Uint()))
+	case reflect.Uint32:
+		e.U32(uint32(v.Uint()))
+	case reflect.Uint64:
+		e.U64(v.Uint())
+	case reflect.Int8:
+		e.I8(int8(v.Int()))
+	case reflect.Int16:
+		e.I16(int16(v.Int()))
+	case reflect.Int32:
+		e.I32(int32(v.Int()))
+	case reflect.Int64:
+		e.I64(v.Int())
+	case reflect.Float32:
+		e.F32(float32(v.Float()))
+	case reflect.Float64:
+		e.F64(v.Float())
+	case reflect.String:
+		if tag == "pubkey" {
+			return e.Pubkey(v.String())
+		}
+		e.String(v.String())
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			e.ByteString(v.Bytes())
+			return nil
+		}
+		e.Len(v.Len())
+		return e.encodeElems(v, tag)
+	case reflect.Array:
+		return e.encodeElems(v, tag)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			tag := f.Tag.Get("borsh")
+			if !f.IsExported() || tag == "-" {
+				continue
+			}
+			if err := e.encodeValue(v.Field(i), tag); err != nil {
+				return fmt.Errorf("%s: %w", f.Name, err)
+			}
+		}
+	default:
+		return fmt.Errorf("borsh: unsupported type %s", v.Type())
+	}
+	return nil
+}
+
+func (e *Encoder) encodeElems(v reflect.Value, tag string) error {
+	for i := 0; i < v.Len(); i++ {
+		if err := e.encodeValue(v.Index(i), tag); err != nil {
+			return fmt.Errorf("[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
+
+func (d *Decoder) decodeValue(v reflect.Value, tag string) error {
+	// As when encoding, pointers are options before anything else.
+	if v.Kind() == reflect.Pointer {
+		present, err := d.Option()
+		if err != nil {
+			return err
+		}
+		if !present {
+			v.Set(reflect.Zero(v.Type()))
+			return nil
+		}
+		v.Set(reflect.New(v.Type().Elem()))
+		return d.decodeValue(v.Elem(), tag)
+	}
+	if v.CanAddr() && v.Addr().Type().Implements(unmarshalerType) {
+		return v.Addr().Interface().(Unmarshaler).UnmarshalBorsh(d)
+	}
+
+	var err error
+	switch v.Kind() {
+	case reflect.Bool:
+		var b bool
+		b, err = d.Bool()
+		v.SetBool(b)
+	case reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		var b []byte
+		if b, err = d.Next(int(v.Type().Size())); err == nil {
+			var n uint64
+			for i := len(b) - 1; i >= 0; i-- {
+				n = n<<8 | uint64(b[i])
+			}
+			v.SetUint(n)
+		}
+	case reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		var b []byte
+		if b, err = d.Next(int(v.Type().Size())); err == nil {
+			var n uint64
+			for i := len(b) - 1; i >= 0; i-- {
+				n = n<<8 | uint64(b[i])
+			}
+			// Sign-extend from the value's width.
+			shift := 64 - 8*uint(len(b))
+			v.SetInt(int64(n<<shift) >> shift)
+		}
+	case reflect.Float32:
+		var f float32
+		f, err = d.F32()
+		v.SetFloat(float64(f))
+	case reflect.Float64:
+		var f float64
+		f, err = d.F64()
+		v.SetFloat(f)
+	case reflect.String:
+		var s string
+		if tag == "pubkey" {
+			s, err = d.Pubkey()
+		} else {
+			s, err = d.String()
+		}
+		v.SetString(s)
+	case reflect.Slice:
+		if v.Type().Elem().Kind() == reflect.Uint8 {
+			var b []byte
+			b, err = d.Bytes()
+			v.SetBytes(b)
+			break
+		}
+		var n int
+		if n, err = d.Len(); err != nil {
+			break
+		}
+		v.Set(reflect.MakeSlice(v.Type(), n, n))
+		err = d.decodeElems(v, tag)
+	case reflect.Array:
+		err = d.decodeElems(v, tag)
+	case reflect.Struct:
+		for i := 0; i < v.NumField(); i++ {
+			f := v.Type().Field(i)
+			tag := f.Tag.Get("borsh")
+			if !f.IsExported() || tag == "-" {
+				continue
+			}
+			if err := d.decodeValue(v.Field(i), tag); err != nil {
+				return fmt.Errorf("%s: %w", f.Name, err)
+			}
+		}
+	default:
+		err = fmt.Errorf("borsh: unsupported type %s", v.Type())
+	}
+	return err
+}
+
+func (d *Decoder) decodeElems(v reflect.Value, tag string) error {
+	for i := 0; i < v.Len(); i++ {
+		if err := d.decodeValue(v.Index(i), tag); err != nil {
+			return fmt.Errorf("[%d]: %w", i, err)
+		}
+	}
+	return nil
+}
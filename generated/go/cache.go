@@ -0,0 +1,230 @@
+package solana
+
+import (
+	"bytes"
+	"container/list"
+	"context"
+	"encoding/json"
+	"sync"
+	"sync/atomic"
+	"time"
+)
+
+// Cache stores raw call results. Implementations must be safe for concurrent
+// use. A backend that can fail, such as Redis, should report failed reads as
+// misses and drop failed writes, so the client falls back to the network.
+type Cache interface {
+	Get(ctx context.Context, key string) ([]byte, bool)
+
+	// Set stores value under key. ttl 0 means the value never expires.
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration)
+}
+
+// CacheRule says when results of one method may be cached and for how long.
+type CacheRule struct {
+	// TTL bounds how long a result is served from the cache. 0 caches it
+	// forever.
+	TTL time.Duration
+
+	// Commitments limits caching to calls made at one of these commitments.
+	// A call that sets none is taken to be at CommitmentFinalized, the
+	// node's default. Empty allows every commitment.
+	Commitments []Commitment
+
+	// InvalidateOnSlot drops a cached result once the client has seen a
+	// response from a later slot than the result's own context slot.
+	// Results without a context slot are not cached under such a rule.
+	InvalidateOnSlot bool
+}
+
+// CachePolicy maps JSON-RPC method names, such as "getBlock", to their cache
+// rules. Methods without a rule are never cached.
+type CachePolicy map[string]CacheRule
+
+// DefaultCachePolicy caches data that cannot change forever, and account
+// reads until the next slot or for at most a second.
+func DefaultCachePolicy() CachePolicy {
+	finalized := []Commitment{CommitmentFinalized}
+	mutable := CacheRule{TTL: time.Second, InvalidateOnSlot: true}
+	return CachePolicy{
+		"getGenesisHash":      {},
+		"getEpochSchedule":    {},
+		"getBlock":            {Commitments: finalized},
+		"getTransaction":      {Commitments: finalized},
+		"getAccountInfo":      mutable,
+		"getMultipleAccounts": mutable,
+		"getBalance":          mutable,
+	}
+}
+
+// WithCache serves calls covered by policy from cache, storing successful
+// non-null results on the way back. A nil policy means DefaultCachePolicy.
+// Errors are never cached.
+func WithCache(cache Cache, policy CachePolicy) ClientOption {
+	return func(c *Client) {
+		if policy == nil {
+			policy = DefaultCachePolicy()
+		}
+		c.cache = &responseCache{cache: cache, policy: policy}
+	}
+}
+
+// responseCache is shared by pointer, so sessions see their client's cache
+// and its observed slot.
+type responseCache struct {
+	cache  Cache
+	policy CachePolicy
+
+	// slot is the highest response context slot seen.
+	slot atomic.Int64
+}
+
+// cacheEntry is the stored form of a result.
+type cacheEntry struct {
+	Slot   Slot            `json:"slot"`
+	Result json.RawMessage `json:"result"`
+}
+
+func (rc *responseCache) wrap(next Invoker) Invoker {
+	return func(ctx context.Context, req *Request) (*RawResponse, error) {
+		rule, ok := rc.policy[req.Method]
+		if !ok || !rule.allows(req.Commitment()) {
+			resp, err := next(ctx, req)
+			rc.observe(resp)
+			return resp, err
+		}
+		key, err := requestKey(ctx, req)
+		if err != nil {
+			return next(ctx, req)
+		}
+
+		if b, ok := rc.cache.Get(ctx, key); ok {
+			var entry cacheEntry
+			if err := json.Unmarshal(b, &entry); err == nil && (!rule.InvalidateOnSlot || entry.Slot >= rc.slot.Load()) {
+				return &RawResponse{ID: req.ID, Result: entry.Result}, nil
+			}
+		}
+
+		resp, err := next(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		rc.observe(resp)
+		if resp.Error != nil || len(resp.Result) == 0 || bytes.Equal(resp.Result, []byte("null")) {
+			return resp, nil
+		}
+
+		slot, ok := resp.ContextSlot()
+		if !ok && rule.InvalidateOnSlot {
+			return resp, nil
+		}
+		b, err := json.Marshal(cacheEntry{Slot: slot, Result: resp.Result})
+		if err == nil {
+			rc.cache.Set(ctx, key, b, rule.TTL)
+		}
+		return resp, nil
+	}
+}
+
+func (rc *responseCache) observe(resp *RawResponse) {
+	if resp == nil {
+		return
+	}
+	slot, ok := resp.ContextSlot()
+	if !ok {
+		return
+	}
+	for {
+		cur := rc.slot.Load()
+		if slot <= cur || rc.slot.CompareAndSwap(cur, slot) {
+			return
+		}
+	}
+}
+
+func (r CacheRule) allows(commitment Commitment) bool {
+	if len(r.Commitments) == 0 {
+		return true
+	}
+	if commitment == "" {
+		commitment = CommitmentFinalized
+	}
+	for _, c := range r.Commitments {
+		if c == commitment {
+			return true
+		}
+	}
+	return false
+}
+
+// LRUCache is an in-memory Cache holding at most a fixed number of entries,
+// evicting the least recently used first.
+type LRUCache struct {
+	mu         sync.Mutex
+	maxEntries int
+	ll         *list.List
+	items      map[string]*list.Element
+}
+
+type lruEntry struct {
+	key     string
+	value   []byte
+	expires time.Time
+}
+
+// NewLRUCache returns an LRUCache holding up to maxEntries results.
+func NewLRUCache(maxEntries int) *LRUCache {
+	return &LRUCache{
+		maxEntries: maxEntries,
+		ll:         list.New(),
+		items:      make(map[string]*list.Element),
+	}
+}
+
+func (c *LRUCache) Get(ctx context.Context, key string) ([]byte, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	el, ok := c.items[key]
+	if !ok {
+		return nil, false
+	}
+	e := el.Value.(*lruEntry)
+	if !e.expires.IsZero() && time.Now().After(e.expires) {
+		c.ll.Remove(el)
+		delete(c.items, key)
+		return nil, false
+	}
+	c.ll.MoveToFront(el)
+	return e.value, true
+}
+
+func (c *LRUCache) Set(ctx context.Context, key string, value []byte, ttl time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	var expires time.Time
+	if ttl > 0 {
+		expires = time.Now().Add(ttl)
+	}
+	if el, ok := c.items[key]; ok {
+		c.ll.MoveToFront(el)
+		e := el.Value.(*lruEntry)
+		e.value, e.expires = value, expires
+		return
+	}
+	c.items[key] = c.ll.PushFront(&lruEntry{key: key, value: value, expires: expires})
+	for c.maxEntries > 0 && c.ll.Len() > c.maxEntries {
+		oldest := c.ll.Back()
+		c.ll.Remove(oldest)
+		delete(c.items, oldest.Value.(*lruEntry).key)
+	}
+}
+
+// Len returns the number of entries held, including expired ones not yet
+// evicted.
+func (c *LRUCache) Len() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.ll.Len()
+}
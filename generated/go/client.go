@@ -5,8 +5,10 @@ import (
 	"context"
 	"encoding/json"
 	"fmt"
+	"log/slog"
 	"net/http"
 	"sync/atomic"
+	"time"
 )
 
 const (
@@ -24,7 +26,7 @@ type rpcRequest struct {
 
 type rpcResponse struct {
 	JsonRPC string          `json:"jsonrpc"`
-	ID      uint64          `json:"id"`
+	ID      *uint64         `json:"id"`
 	Result  json.RawMessage `json:"result"`
 	Error   *RPCError       `json:"error,omitempty"`
 }
@@ -39,66 +41,185 @@ func (e *RPCError) Error() string {
 	return fmt.Sprintf("RPC error %d: %s", e.Code, e.Message)
 }
 
+// validator is implemented by params that can be checked locally before a
+// request goes out.
+type validator interface {
+	Validate() error
+}
+
 type Client struct {
 	endpoint  string
 	httpClient *http.Client
 	requestID  uint64
+
+	defaultCommitment Commitment
+	session           *Session
+	interceptors      []Interceptor
+	invoker           Invoker
+	logger            *slog.Logger
+	logBodyLimit      int
+	maxResponseBytes  int64
+	bodyReadTimeout   time.Duration
+	compression       *compression
+	limiter           *limiter
+	dedup             *dedup
+	cache             *responseCache
 }
 
-func NewClient(endpoint string) *Client {
-	return &Client{
-		endpoint:   endpoint,
-		httpClient: http.DefaultClient,
+func NewClient(endpoint string, opts ...ClientOption) *Client {
+	c := &Client{
+		endpoint:    endpoint,
+		httpClient:  http.DefaultClient,
+		compression: newCompression(),
 	}
+	for _, opt := range opts {
+		opt(c)
+	}
+	c.invoker = chainInterceptors(c.interceptors, c.transport())
+	return c
 }
 
-func (c *Client) call(ctx context.Context, method string, params []interface{}, result interface{}) error {
-	id := atomic.AddUint64(&c.requestID, 1)
+func (c *Client) newRequest(method string, params []interface{}) *Request {
+	if params == nil {
+		params = []interface{}{}
+	}
+
+	return &Request{
+		ID:       atomic.AddUint64(&c.requestID, 1),
+		Endpoint: c.endpoint,
+		Method:   method,
+		Params:   params,
+		Header:   make(http.Header),
+	}
+}
+
+func (c *Client) newHTTPRequest(ctx context.Context, r *Request) (*http.Request, error) {
+	for _, p := range r.Params {
+		if v, ok := p.(validator); ok {
+			if err := v.Validate(); err != nil {
+				return nil, fmt.Errorf("invalid %s params: %w", r.Method, err)
+			}
+		}
+	}
 
 	reqBody := rpcRequest{
 		JsonRPC: "2.0",
-		ID:      id,
-		Method:  method,
-		Params:  params,
+		ID:      r.ID,
+		Method:  r.Method,
+		Params:  r.Params,
 	}
 
 	body, err := json.Marshal(reqBody)
 	if err != nil {
-		return fmt.Errorf("marshal request: %w", err)
+		return nil, fmt.Errorf("marshal request: %w", err)
 	}
 
-	req, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewReader(body))
+	req, err := http.NewRequestWithContext(ctx, "POST", r.Endpoint, bytes.NewReader(body))
 	if err != nil {
-		return fmt.Errorf("create request: %w", err)
+		return nil, fmt.Errorf("create request: %w", err)
+	}
+	for k, v := range r.Header {
+		req.Header[k] = v
 	}
 	req.Header.Set("Content-Type", "application/json")
+	c.compression.setAccept(req)
+	return req, nil
+}
+
+func (c *Client) roundTrip(ctx context.Context, r *Request) (*RawResponse, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	req, err := c.newHTTPRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
 
 	resp, err := c.httpClient.Do(req)
 	if err != nil {
-		return fmt.Errorf("do request: %w", err)
+		return nil, fmt.Errorf("do request: %w", redactURLError(err))
 	}
 	defer resp.Body.Close()
 
+	body, err := c.readBody(ctx, r, resp, cancel)
+	if err != nil {
+		return nil, err
+	}
+
 	var rpcResp rpcResponse
-	if err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {
-		return fmt.Errorf("decode response: %w", err)
+	if err := json.Unmarshal(body, &rpcResp); err != nil {
+		if err := checkStatus(r, resp.StatusCode, body, nil); err != nil {
+			return nil, err
+		}
+		return nil, fmt.Errorf("decode response: %w", err)
+	}
+	if err := checkStatus(r, resp.StatusCode, body, &rpcResp); err != nil {
+		return nil, err
+	}
+	if err := checkResponse(r, &rpcResp); err != nil {
+		return nil, err
+	}
+
+	return &RawResponse{
+		ID:         r.ID,
+		Result:     rpcResp.Result,
+		Error:      rpcResp.Error,
+		StatusCode: resp.StatusCode,
+		Header:     resp.Header,
+		BodySize:   int64(len(body)),
+	}, nil
+}
+
+func (c *Client) send(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
+	resp, err := c.invoker(ctx, c.newRequest(method, params))
+	if err != nil {
+		return nil, err
 	}
 
-	if rpcResp.Error != nil {
-		return rpcResp.Error
+	if resp.Error != nil {
+		return nil, resp.Error
 	}
 
-	if err := json.Unmarshal(rpcResp.Result, result); err != nil {
+	return resp.Result, nil
+}
+
+func (c *Client) call(ctx context.Context, method string, params []interface{}, result interface{}) error {
+	var raw json.RawMessage
+	var err error
+	if c.session != nil {
+		raw, err = c.session.send(ctx, method, params)
+	} else {
+		raw, err = c.send(ctx, method, params)
+	}
+	if err != nil {
+		return err
+	}
+
+	if err := json.Unmarshal(raw, result); err != nil {
 		return fmt.Errorf("unmarshal result: %w", err)
 	}
 
 	return nil
 }
 
+// Call invokes any RPC method and decodes its result into result. It covers
+// provider-specific methods and ones the spec does not describe yet.
+func (c *Client) Call(ctx context.Context, method string, params []interface{}, result interface{}) error {
+	return c.call(ctx, method, params, result)
+}
+
+// CallRaw invokes any RPC method and returns its result undecoded.
+func (c *Client) CallRaw(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
+	var result json.RawMessage
+	err := c.call(ctx, method, params, &result)
+	return result, err
+}
+
 // GetAccountInfo Returns all information associated with the account of provided Pubkey
 func (c *Client) GetAccountInfo(ctx context.Context, pubkey Pubkey, config *GetAccountInfoConfig) (AccountInfoResponse, error) {
 	params := make([]interface{}, 0)
 	params = append(params, pubkey)
+	config = withDefaults(c.defaults(ctx, "getAccountInfo"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -112,6 +233,7 @@ func (c *Client) GetAccountInfo(ctx context.Context, pubkey Pubkey, config *GetA
 func (c *Client) GetBalance(ctx context.Context, pubkey Pubkey, config *CommitmentConfig) (RpcResponseU64, error) {
 	params := make([]interface{}, 0)
 	params = append(params, pubkey)
+	config = withDefaults(c.defaults(ctx, "getBalance"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -125,6 +247,7 @@ func (c *Client) GetBalance(ctx context.Context, pubkey Pubkey, config *Commitme
 func (c *Client) GetBlock(ctx context.Context, slot Slot, config *GetBlockConfig) (Block, error) {
 	params := make([]interface{}, 0)
 	params = append(params, slot)
+	config = withDefaults(c.defaults(ctx, "getBlock"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -147,6 +270,7 @@ func (c *Client) GetBlockCommitment(ctx context.Context, slot Slot) (BlockCommit
 // GetBlockHeight Returns the current block height of the node
 func (c *Client) GetBlockHeight(ctx context.Context, config *CommitmentConfig) (int64, error) {
 	params := make([]interface{}, 0)
+	config = withDefaults(c.defaults(ctx, "getBlockHeight"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -159,6 +283,7 @@ func (c *Client) GetBlockHeight(ctx context.Context, config *CommitmentConfig) (
 // GetBlockProduction Returns recent block production information from the current or previous epoch
 func (c *Client) GetBlockProduction(ctx context.Context, config *GetBlockProductionConfig) (BlockProduction, error) {
 	params := make([]interface{}, 0)
+	config = withDefaults(c.defaults(ctx, "getBlockProduction"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -185,6 +310,7 @@ func (c *Client) GetBlocks(ctx context.Context, startSlot Slot, endSlot *Slot, c
 	if endSlot != nil {
 		params = append(params, *endSlot)
 	}
+	config = withDefaults(c.defaults(ctx, "getBlocks"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -199,6 +325,7 @@ func (c *Client) GetBlocksWithLimit(ctx context.Context, startSlot Slot, limit i
 	params := make([]interface{}, 0)
 	params = append(params, startSlot)
 	params = append(params, limit)
+	config = withDefaults(c.defaults(ctx, "getBlocksWithLimit"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -220,6 +347,7 @@ func (c *Client) GetClusterNodes(ctx context.Context) ([]ClusterNode, error) {
 // GetEpochInfo Returns information about the current epoch
 func (c *Client) GetEpochInfo(ctx context.Context, config *CommitmentConfig) (EpochInfo, error) {
 	params := make([]interface{}, 0)
+	config = withDefaults(c.defaults(ctx, "getEpochInfo"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -242,6 +370,7 @@ func (c *Client) GetEpochSchedule(ctx context.Context) (EpochSchedule, error) {
 func (c *Client) GetFeeForMessage(ctx context.Context, message string, config *CommitmentConfig) (RpcResponseU64, error) {
 	params := make([]interface{}, 0)
 	params = append(params, message)
+	config = withDefaults(c.defaults(ctx, "getFeeForMessage"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -299,6 +428,7 @@ func (c *Client) GetIdentity(ctx context.Context) (map[string]interface{}, error
 // GetInflationGovernor Returns the current inflation governor
 func (c *Client) GetInflationGovernor(ctx context.Context, config *CommitmentConfig) (InflationGovernor, error) {
 	params := make([]interface{}, 0)
+	config = withDefaults(c.defaults(ctx, "getInflationGovernor"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -321,6 +451,7 @@ func (c *Client) GetInflationRate(ctx context.Context) (InflationRate, error) {
 func (c *Client) GetInflationReward(ctx context.Context, addresses []Pubkey, config *GetInflationRewardConfig) ([]InflationReward, error) {
 	params := make([]interface{}, 0)
 	params = append(params, addresses)
+	config = withDefaults(c.defaults(ctx, "getInflationReward"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -333,6 +464,7 @@ func (c *Client) GetInflationReward(ctx context.Context, addresses []Pubkey, con
 // GetLargestAccounts Returns the 20 largest accounts, by lamport balance
 func (c *Client) GetLargestAccounts(ctx context.Context, config *GetLargestAccountsConfig) (RpcResponseLargestAccounts, error) {
 	params := make([]interface{}, 0)
+	config = withDefaults(c.defaults(ctx, "getLargestAccounts"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -345,6 +477,7 @@ func (c *Client) GetLargestAccounts(ctx context.Context, config *GetLargestAccou
 // GetLatestBlockhash Returns the latest blockhash
 func (c *Client) GetLatestBlockhash(ctx context.Context, config *CommitmentConfig) (LatestBlockhashResponse, error) {
 	params := make([]interface{}, 0)
+	config = withDefaults(c.defaults(ctx, "getLatestBlockhash"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -360,6 +493,7 @@ func (c *Client) GetLeaderSchedule(ctx context.Context, slot *Slot, config *GetL
 	if slot != nil {
 		params = append(params, *slot)
 	}
+	config = withDefaults(c.defaults(ctx, "getLeaderSchedule"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -391,6 +525,7 @@ func (c *Client) GetMaxShredInsertSlot(ctx context.Context) (Slot, error) {
 func (c *Client) GetMinimumBalanceForRentExemption(ctx context.Context, dataLength int64, config *CommitmentConfig) (int64, error) {
 	params := make([]interface{}, 0)
 	params = append(params, dataLength)
+	config = withDefaults(c.defaults(ctx, "getMinimumBalanceForRentExemption"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -404,6 +539,7 @@ func (c *Client) GetMinimumBalanceForRentExemption(ctx context.Context, dataLeng
 func (c *Client) GetMultipleAccounts(ctx context.Context, pubkeys []Pubkey, config *GetAccountInfoConfig) (MultipleAccountsResponse, error) {
 	params := make([]interface{}, 0)
 	params = append(params, pubkeys)
+	config = withDefaults(c.defaults(ctx, "getMultipleAccounts"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -417,6 +553,7 @@ func (c *Client) GetMultipleAccounts(ctx context.Context, pubkeys []Pubkey, conf
 func (c *Client) GetProgramAccounts(ctx context.Context, programId Pubkey, config *GetProgramAccountsConfig) ([]ProgramAccount, error) {
 	params := make([]interface{}, 0)
 	params = append(params, programId)
+	config = withDefaults(c.defaults(ctx, "getProgramAccounts"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -467,6 +604,7 @@ func (c *Client) GetSignatureStatuses(ctx context.Context, signatures []Signatur
 func (c *Client) GetSignaturesForAddress(ctx context.Context, address Pubkey, config *GetSignaturesForAddressConfig) ([]SignatureInfo, error) {
 	params := make([]interface{}, 0)
 	params = append(params, address)
+	config = withDefaults(c.defaults(ctx, "getSignaturesForAddress"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -479,6 +617,7 @@ func (c *Client) GetSignaturesForAddress(ctx context.Context, address Pubkey, co
 // GetSlot Returns the slot that has reached the given or default commitment level
 func (c *Client) GetSlot(ctx context.Context, config *CommitmentConfig) (Slot, error) {
 	params := make([]interface{}, 0)
+	config = withDefaults(c.defaults(ctx, "getSlot"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -491,6 +630,7 @@ func (c *Client) GetSlot(ctx context.Context, config *CommitmentConfig) (Slot, e
 // GetSlotLeader Returns the current slot leader
 func (c *Client) GetSlotLeader(ctx context.Context, config *CommitmentConfig) (Pubkey, error) {
 	params := make([]interface{}, 0)
+	config = withDefaults(c.defaults(ctx, "getSlotLeader"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -514,6 +654,7 @@ func (c *Client) GetSlotLeaders(ctx context.Context, startSlot Slot, limit int64
 // GetStakeMinimumDelegation Returns the stake minimum delegation, in lamports
 func (c *Client) GetStakeMinimumDelegation(ctx context.Context, config *CommitmentConfig) (RpcResponseU64, error) {
 	params := make([]interface{}, 0)
+	config = withDefaults(c.defaults(ctx, "getStakeMinimumDelegation"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -526,6 +667,7 @@ func (c *Client) GetStakeMinimumDelegation(ctx context.Context, config *Commitme
 // GetSupply Returns information about the current supply
 func (c *Client) GetSupply(ctx context.Context, config *GetSupplyConfig) (SupplyResponse, error) {
 	params := make([]interface{}, 0)
+	config = withDefaults(c.defaults(ctx, "getSupply"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -539,6 +681,7 @@ func (c *Client) GetSupply(ctx context.Context, config *GetSupplyConfig) (Supply
 func (c *Client) GetTokenAccountBalance(ctx context.Context, pubkey Pubkey, config *CommitmentConfig) (TokenBalanceResponse, error) {
 	params := make([]interface{}, 0)
 	params = append(params, pubkey)
+	config = withDefaults(c.defaults(ctx, "getTokenAccountBalance"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -553,6 +696,7 @@ func (c *Client) GetTokenAccountsByDelegate(ctx context.Context, delegate Pubkey
 	params := make([]interface{}, 0)
 	params = append(params, delegate)
 	params = append(params, filter)
+	config = withDefaults(c.defaults(ctx, "getTokenAccountsByDelegate"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -567,6 +711,7 @@ func (c *Client) GetTokenAccountsByOwner(ctx context.Context, owner Pubkey, filt
 	params := make([]interface{}, 0)
 	params = append(params, owner)
 	params = append(params, filter)
+	config = withDefaults(c.defaults(ctx, "getTokenAccountsByOwner"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -580,6 +725,7 @@ func (c *Client) GetTokenAccountsByOwner(ctx context.Context, owner Pubkey, filt
 func (c *Client) GetTokenLargestAccounts(ctx context.Context, mint Pubkey, config *CommitmentConfig) (TokenLargestAccountsResponse, error) {
 	params := make([]interface{}, 0)
 	params = append(params, mint)
+	config = withDefaults(c.defaults(ctx, "getTokenLargestAccounts"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -593,6 +739,7 @@ func (c *Client) GetTokenLargestAccounts(ctx context.Context, mint Pubkey, confi
 func (c *Client) GetTokenSupply(ctx context.Context, mint Pubkey, config *CommitmentConfig) (TokenBalanceResponse, error) {
 	params := make([]interface{}, 0)
 	params = append(params, mint)
+	config = withDefaults(c.defaults(ctx, "getTokenSupply"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -606,6 +753,7 @@ func (c *Client) GetTokenSupply(ctx context.Context, mint Pubkey, config *Commit
 func (c *Client) GetTransaction(ctx context.Context, signature Signature, config *GetTransactionConfig) (TransactionResponse, error) {
 	params := make([]interface{}, 0)
 	params = append(params, signature)
+	config = withDefaults(c.defaults(ctx, "getTransaction"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -618,6 +766,7 @@ func (c *Client) GetTransaction(ctx context.Context, signature Signature, config
 // GetTransactionCount Returns the current Transaction count from the ledger
 func (c *Client) GetTransactionCount(ctx context.Context, config *CommitmentConfig) (int64, error) {
 	params := make([]interface{}, 0)
+	config = withDefaults(c.defaults(ctx, "getTransactionCount"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -639,6 +788,7 @@ func (c *Client) GetVersion(ctx context.Context) (Version, error) {
 // GetVoteAccounts Returns the account info and associated stake for all the voting accounts in the current bank
 func (c *Client) GetVoteAccounts(ctx context.Context, config *GetVoteAccountsConfig) (VoteAccountsResponse, error) {
 	params := make([]interface{}, 0)
+	config = withDefaults(c.defaults(ctx, "getVoteAccounts"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -652,6 +802,7 @@ func (c *Client) GetVoteAccounts(ctx context.Context, config *GetVoteAccountsCon
 func (c *Client) IsBlockhashValid(ctx context.Context, blockhash Hash, config *CommitmentConfig) (RpcResponseBool, error) {
 	params := make([]interface{}, 0)
 	params = append(params, blockhash)
+	config = withDefaults(c.defaults(ctx, "isBlockhashValid"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -675,6 +826,7 @@ func (c *Client) RequestAirdrop(ctx context.Context, pubkey Pubkey, lamports int
 	params := make([]interface{}, 0)
 	params = append(params, pubkey)
 	params = append(params, lamports)
+	config = withDefaults(c.defaults(ctx, "requestAirdrop"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -688,6 +840,7 @@ func (c *Client) RequestAirdrop(ctx context.Context, pubkey Pubkey, lamports int
 func (c *Client) SendTransaction(ctx context.Context, transaction string, config *SendTransactionConfig) (Signature, error) {
 	params := make([]interface{}, 0)
 	params = append(params, transaction)
+	config = withDefaults(c.defaults(ctx, "sendTransaction"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -701,6 +854,7 @@ func (c *Client) SendTransaction(ctx context.Context, transaction string, config
 func (c *Client) SimulateTransaction(ctx context.Context, transaction string, config *SimulateTransactionConfig) (SimulateTransactionResponse, error) {
 	params := make([]interface{}, 0)
 	params = append(params, transaction)
+	config = withDefaults(c.defaults(ctx, "simulateTransaction"), config)
 	if config != nil {
 		params = append(params, *config)
 	}
@@ -0,0 +1,52 @@
+// Command anchor-gen generates a Go package with typed bindings for an Anchor
+// program from its IDL.
+//
+//	anchor-gen -idl target/idl/counter.json -out counter/counter.go
+//
+// The package is named after the program unless -package is given. See
+// package anchorgen for what it contains.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/solana-rpc/client/anchor"
+	"github.com/solana-rpc/client/anchor/anchorgen"
+)
+
+func main() {
+	idlPath := flag.String("idl", "", "path of the IDL JSON file")
+	pkg := flag.String("package", "", "name of the generated package (default: the program name)")
+	out := flag.String("out", "", "file to write (default: standard output)")
+	flag.Parse()
+
+	if err := run(*idlPath, *pkg, *out); err != nil {
+		fmt.Fprintln(os.Stderr, "anchor-gen:", err)
+		os.Exit(1)
+	}
+}
+
+func run(idlPath, pkg, out string) error {
+	if idlPath == "" {
+		return fmt.Errorf("-idl is required")
+	}
+	idl, err := anchor.LoadIDLFile(idlPath)
+	if err != nil {
+		return err
+	}
+	src, err := anchorgen.Generate(idl, pkg)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(out), 0o755); err != nil {
+		return err
+	}
+	return os.WriteFile(out, src, 0o644)
+}
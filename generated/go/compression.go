@@ -0,0 +1,188 @@
+package solana
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+)
+
+// Decompressor wraps a response body sent with a Content-Encoding the client
+// advertised in Accept-Encoding.
+type Decompressor func(r io.Reader) (io.ReadCloser, error)
+
+// CompressionStats counts response bytes as received on the wire and after
+// decompression. Uncompressed responses count the same on both sides.
+type CompressionStats struct {
+	Responses           int64
+	CompressedResponses int64
+	WireBytes           int64
+	DecodedBytes        int64
+}
+
+// SavedBytes is how many fewer bytes were transferred than decoded.
+func (s CompressionStats) SavedBytes() int64 {
+	return s.DecodedBytes - s.WireBytes
+}
+
+// Ratio is DecodedBytes over WireBytes, or 0 before any response was read.
+func (s CompressionStats) Ratio() float64 {
+	if s.WireBytes == 0 {
+		return 0
+	}
+	return float64(s.DecodedBytes) / float64(s.WireBytes)
+}
+
+// compression holds the decoders a client advertises and its byte counters.
+// It is shared by pointer, so sessions count toward their client's stats.
+type compression struct {
+	mu       sync.RWMutex
+	decoders map[string]Decompressor
+	accept   string
+
+	responses           atomic.Int64
+	compressedResponses atomic.Int64
+	wireBytes           atomic.Int64
+	decodedBytes        atomic.Int64
+}
+
+func newCompression() *compression {
+	c := &compression{decoders: map[string]Decompressor{
+		"gzip": func(r io.Reader) (io.ReadCloser, error) {
+			return gzip.NewReader(r)
+		},
+		"deflate": newDeflateReader,
+	}}
+	c.updateAccept()
+	return c
+}
+
+// WithDecompressor adds a decoder for a Content-Encoding such as "zstd" or
+// "br", which the standard library lacks, and advertises it on every request.
+// It replaces the built-in gzip or deflate decoder when given that name.
+func WithDecompressor(encoding string, d Decompressor) ClientOption {
+	return func(c *Client) {
+		c.compression.mu.Lock()
+		defer c.compression.mu.Unlock()
+		c.compression.decoders[strings.ToLower(encoding)] = d
+		c.compression.updateAccept()
+	}
+}
+
+// WithoutCompression stops the client from asking for compressed responses.
+// Responses are then decoded only by the HTTP client's own transport.
+func WithoutCompression() ClientOption {
+	return func(c *Client) {
+		c.compression.mu.Lock()
+		defer c.compression.mu.Unlock()
+		c.compression.decoders = map[string]Decompressor{}
+		c.compression.updateAccept()
+	}
+}
+
+// CompressionStats reports response byte counts since the client was created,
+// including calls made through its sessions.
+func (c *Client) CompressionStats() CompressionStats {
+	return CompressionStats{
+		Responses:           c.compression.responses.Load(),
+		CompressedResponses: c.compression.compressedResponses.Load(),
+		WireBytes:           c.compression.wireBytes.Load(),
+		DecodedBytes:        c.compression.decodedBytes.Load(),
+	}
+}
+
+// updateAccept rebuilds the Accept-Encoding value. c.mu must be held.
+func (c *compression) updateAccept() {
+	names := make([]string, 0, len(c.decoders))
+	for name := range c.decoders {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	c.accept = strings.Join(names, ", ")
+}
+
+// setAccept advertises the client's decoders on req. A header set by an
+// interceptor is left alone, though its encodings must still be decodable.
+// Setting the header also keeps net/http from decoding gzip on its own, which
+// would hide the wire size.
+func (c *compression) setAccept(req *http.Request) {
+	c.mu.RLock()
+	accept := c.accept
+	c.mu.RUnlock()
+
+	if accept != "" && req.Header.Get("Accept-Encoding") == "" {
+		req.Header.Set("Accept-Encoding", accept)
+	}
+}
+
+// decodedBody is a response body with its Content-Encoding removed. Closing it
+// releases the decompressor but not the underlying response body.
+type decodedBody struct {
+	io.ReadCloser
+	wire       *countingReader
+	compressed bool
+}
+
+// decode returns resp's body with its Content-Encoding removed, reading
+// through a counter so the caller can report wire bytes. The caller must close
+// the result.
+func (c *compression) decode(resp *http.Response) (*decodedBody, error) {
+	wire := &countingReader{r: resp.Body}
+
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if encoding == "" || encoding == "identity" {
+		return &decodedBody{ReadCloser: io.NopCloser(wire), wire: wire}, nil
+	}
+
+	c.mu.RLock()
+	d, ok := c.decoders[encoding]
+	c.mu.RUnlock()
+	if !ok {
+		return nil, fmt.Errorf("unsupported response Content-Encoding %q", encoding)
+	}
+
+	body, err := d(wire)
+	if err != nil {
+		return nil, fmt.Errorf("decode %s response: %w", encoding, err)
+	}
+	return &decodedBody{ReadCloser: body, wire: wire, compressed: true}, nil
+}
+
+// record counts a response body that was read in full.
+func (c *compression) record(body *decodedBody, decodedBytes int64) {
+	c.responses.Add(1)
+	if body.compressed {
+		c.compressedResponses.Add(1)
+	}
+	c.wireBytes.Add(body.wire.n)
+	c.decodedBytes.Add(decodedBytes)
+}
+
+type countingReader struct {
+	r io.Reader
+	n int64
+}
+
+func (r *countingReader) Read(p []byte) (int, error) {
+	n, err := r.r.Read(p)
+	r.n += int64(n)
+	return n, err
+}
+
+// newDeflateReader accepts both zlib-wrapped deflate, which HTTP specifies,
+// and the raw deflate some servers send instead.
+func newDeflateReader(r io.Reader) (io.ReadCloser, error) {
+	br := bufio.NewReader(r)
+	header, err := br.Peek(2)
+	if err == nil && header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(br)
+	}
+	return flate.NewReader(br), nil
+}
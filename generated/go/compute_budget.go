@@ -0,0 +1,48 @@
+package solana
+
+import "encoding/binary"
+
+// ComputeBudgetProgramID is the address of the compute budget program.
+const ComputeBudgetProgramID Pubkey = "ComputeBudget111111111111111111111111111111"
+
+// Compute budget instruction discriminators.
+const (
+	computeBudgetRequestHeapFrame               = 1
+	computeBudgetSetComputeUnitLimit            = 2
+	computeBudgetSetComputeUnitPrice            = 3
+	computeBudgetSetLoadedAccountsDataSizeLimit = 4
+)
+
+// SetComputeUnitLimit returns an instruction capping the transaction at
+// units compute units.
+func SetComputeUnitLimit(units uint32) Instruction {
+	return computeBudgetU32(computeBudgetSetComputeUnitLimit, units)
+}
+
+// SetComputeUnitPrice returns an instruction setting the transaction's
+// priority fee in micro-lamports per compute unit.
+func SetComputeUnitPrice(microLamports uint64) Instruction {
+	data := make([]byte, 9)
+	data[0] = computeBudgetSetComputeUnitPrice
+	binary.LittleEndian.PutUint64(data[1:], microLamports)
+	return Instruction{ProgramID: ComputeBudgetProgramID, Data: data}
+}
+
+// RequestHeapFrame returns an instruction requesting a heap of bytes bytes
+// for each program the transaction invokes.
+func RequestHeapFrame(bytes uint32) Instruction {
+	return computeBudgetU32(computeBudgetRequestHeapFrame, bytes)
+}
+
+// SetLoadedAccountsDataSizeLimit returns an instruction capping the total
+// account data the transaction may load.
+func SetLoadedAccountsDataSizeLimit(bytes uint32) Instruction {
+	return computeBudgetU32(computeBudgetSetLoadedAccountsDataSizeLimit, bytes)
+}
+
+func computeBudgetU32(discriminator byte, v uint32) Instruction {
+	data := make([]byte, 5)
+	data[0] = discriminator
+	binary.LittleEndian.PutUint32(data[1:], v)
+	return Instruction{ProgramID: ComputeBudgetProgramID, Data: data}
+}
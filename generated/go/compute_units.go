@@ -0,0 +1,174 @@
+package solana
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math"
+)
+
+const (
+	// MaxComputeUnitLimit is the most compute units a transaction may use.
+	MaxComputeUnitLimit = 1_400_000
+
+	// DefaultComputeUnitMargin is the fraction added to the simulated
+	// compute units when EstimateComputeUnitsConfig sets no margin.
+	DefaultComputeUnitMargin = 0.1
+)
+
+// ErrSimulationFailed is matched by errors.Is when a simulated transaction
+// fails.
+var ErrSimulationFailed = errors.New("transaction simulation failed")
+
+// SimulationError describes a simulation rejected with ErrSimulationFailed.
+type SimulationError struct {
+	Err  *TransactionError
+	Logs []string
+}
+
+func (e *SimulationError) Error() string {
+	return fmt.Sprintf("%v: %v", ErrSimulationFailed, e.Err)
+}
+
+func (e *SimulationError) Unwrap() error {
+	return ErrSimulationFailed
+}
+
+// EstimateComputeUnitsConfig configures EstimateComputeUnits.
+type EstimateComputeUnitsConfig struct {
+	// Margin is the fraction added to the simulated compute units, so
+	// small differences in on-chain state do not push the transaction over
+	// its limit. The default is DefaultComputeUnitMargin; use a negative
+	// value for no margin.
+	Margin float64
+
+	// Commitment is the commitment the simulation runs at.
+	Commitment Commitment
+}
+
+// ComputeUnitEstimate is the result of EstimateComputeUnits.
+type ComputeUnitEstimate struct {
+	UnitsConsumed int64
+	Limit         uint32
+
+	// Transaction is the input transaction with its compute unit limit set
+	// to Limit. Its message has changed, so its signatures are zeroed and it
+	// must be signed again before sending.
+	Transaction *Transaction
+}
+
+// EstimateComputeUnits simulates tx with c, typically a Client or Session, to
+// measure the compute units it consumes and returns it rebuilt with a
+// SetComputeUnitLimit instruction for that amount plus a margin. An existing
+// limit instruction is replaced, otherwise one is added first. tx does not
+// need to be signed and its blockhash may be stale: the simulation skips
+// signature checks and replaces the blockhash. config may be nil.
+func EstimateComputeUnits(ctx context.Context, c RPC, tx *Transaction, config *EstimateComputeUnitsConfig) (ComputeUnitEstimate, error) {
+	cfg := EstimateComputeUnitsConfig{Margin: DefaultComputeUnitMargin}
+	if config != nil {
+		cfg = *config
+		if cfg.Margin == 0 {
+			cfg.Margin = DefaultComputeUnitMargin
+		}
+		if cfg.Margin < 0 {
+			cfg.Margin = 0
+		}
+	}
+
+	// Simulate at the maximum limit so the default per-instruction limit
+	// does not cut the measurement short. The instruction itself costs the
+	// same at any limit, so the count carries over.
+	probe, err := WithComputeUnitLimit(tx, MaxComputeUnitLimit)
+	if err != nil {
+		return ComputeUnitEstimate{}, err
+	}
+	wire, err := probe.MarshalBinary()
+	if err != nil {
+		return ComputeUnitEstimate{}, fmt.Errorf("encode transaction: %w", err)
+	}
+
+	resp, err := c.SimulateTransaction(ctx, base64.StdEncoding.EncodeToString(wire), &SimulateTransactionConfig{
+		Encoding:               "base64",
+		Commitment:             cfg.Commitment,
+		ReplaceRecentBlockhash: true,
+		SigVerify:              false,
+	})
+	if err != nil {
+		return ComputeUnitEstimate{}, err
+	}
+	if resp.Value.Err != nil {
+		return ComputeUnitEstimate{}, &SimulationError{Err: resp.Value.Err, Logs: resp.Value.Logs}
+	}
+
+	consumed := resp.Value.UnitsConsumed
+	limit := math.Ceil(float64(consumed) * (1 + cfg.Margin))
+	if limit > MaxComputeUnitLimit {
+		limit = MaxComputeUnitLimit
+	}
+	rebuilt, err := WithComputeUnitLimit(tx, uint32(limit))
+	if err != nil {
+		return ComputeUnitEstimate{}, err
+	}
+	return ComputeUnitEstimate{UnitsConsumed: consumed, Limit: uint32(limit), Transaction: rebuilt}, nil
+}
+
+// WithComputeUnitLimit returns a copy of tx whose compute unit limit is
+// units. An existing SetComputeUnitLimit instruction is updated in place;
+// otherwise one is inserted before the other instructions, adding the
+// compute budget program to the account keys if needed. tx is not modified.
+// The copy's signatures are zeroed, since its message differs from the one
+// they signed.
+func WithComputeUnitLimit(tx *Transaction, units uint32) (*Transaction, error) {
+	msg := tx.Message
+	msg.AccountKeys = append([]Pubkey(nil), msg.AccountKeys...)
+	msg.Instructions = append([]CompiledInstruction(nil), msg.Instructions...)
+	limit := SetComputeUnitLimit(units)
+
+	replaced := false
+	for i, ix := range msg.Instructions {
+		if int(ix.ProgramIDIndex) < len(msg.AccountKeys) && msg.AccountKeys[ix.ProgramIDIndex] == ComputeBudgetProgramID &&
+			len(ix.Data) > 0 && ix.Data[0] == computeBudgetSetComputeUnitLimit {
+			msg.Instructions[i].Data = limit.Data
+			replaced = true
+		}
+	}
+
+	if !replaced {
+		index := -1
+		for i, key := range msg.AccountKeys {
+			if key == ComputeBudgetProgramID {
+				index = i
+				break
+			}
+		}
+		if index < 0 {
+			// Append as a read-only non-signer, the last group of static
+			// keys. Indexes past the static keys point at lookup table
+			// addresses and shift up by one.
+			index = len(msg.AccountKeys)
+			if index >= math.MaxUint8 {
+				return nil, errors.New("transaction has too many account keys to add the compute budget program")
+			}
+			msg.AccountKeys = append(msg.AccountKeys, ComputeBudgetProgramID)
+			msg.Header.NumReadonlyUnsignedAccounts++
+			for i, ix := range msg.Instructions {
+				accounts := make([]uint8, len(ix.Accounts))
+				for j, a := range ix.Accounts {
+					if int(a) >= index {
+						a++
+					}
+					accounts[j] = a
+				}
+				msg.Instructions[i].Accounts = accounts
+			}
+		}
+		msg.Instructions = append([]CompiledInstruction{{ProgramIDIndex: uint8(index), Data: limit.Data}}, msg.Instructions...)
+	}
+
+	signatures := make([]Signature, msg.Header.NumRequiredSignatures)
+	for i := range signatures {
+		signatures[i] = EncodeBase58(make([]byte, signatureLength))
+	}
+	return &Transaction{Signatures: signatures, Message: msg}, nil
+}
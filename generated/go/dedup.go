@@ -0,0 +1,189 @@
+package solana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sync"
+)
+
+// deduplicated lists the read-only methods that may be collapsed. Any other
+// method, including ones called through CallRaw that the client does not
+// know, is sent once per call.
+var deduplicated = map[string]bool{
+	"getAccountInfo":                    true,
+	"getBalance":                        true,
+	"getBlock":                          true,
+	"getBlockCommitment":                true,
+	"getBlockHeight":                    true,
+	"getBlockProduction":                true,
+	"getBlockTime":                      true,
+	"getBlocks":                         true,
+	"getBlocksWithLimit":                true,
+	"getClusterNodes":                   true,
+	"getEpochInfo":                      true,
+	"getEpochSchedule":                  true,
+	"getFeeForMessage":                  true,
+	"getFirstAvailableBlock":            true,
+	"getGenesisHash":                    true,
+	"getHealth":                         true,
+	"getHighestSnapshotSlot":            true,
+	"getIdentity":                       true,
+	"getInflationGovernor":              true,
+	"getInflationRate":                  true,
+	"getInflationReward":                true,
+	"getLargestAccounts":                true,
+	"getLatestBlockhash":                true,
+	"getLeaderSchedule":                 true,
+	"getMaxRetransmitSlot":              true,
+	"getMaxShredInsertSlot":             true,
+	"getMinimumBalanceForRentExemption": true,
+	"getMultipleAccounts":               true,
+	"getProgramAccounts":                true,
+	"getRecentPerformanceSamples":       true,
+	"getRecentPrioritizationFees":       true,
+	"getSignatureStatuses":              true,
+	"getSignaturesForAddress":           true,
+	"getSlot":                           true,
+	"getSlotLeader":                     true,
+	"getSlotLeaders":                    true,
+	"getStakeMinimumDelegation":         true,
+	"getSupply":                         true,
+	"getTokenAccountBalance":            true,
+	"getTokenAccountsByDelegate":        true,
+	"getTokenAccountsByOwner":           true,
+	"getTokenLargestAccounts":           true,
+	"getTokenSupply":                    true,
+	"getTransaction":                    true,
+	"getTransactionCount":               true,
+	"getVersion":                        true,
+	"getVoteAccounts":                   true,
+	"isBlockhashValid":                  true,
+	"minimumLedgerSlot":                 true,
+	"simulateTransaction":               true,
+}
+
+// WithDeduplication collapses identical concurrent calls into one request.
+// Calls are identical when they go to the same endpoint with the same method,
+// headers and response size cap (see WithMaxResponseBytes), and the same
+// params after canonicalization, so an omitted config matches an empty one.
+// Every caller gets the shared result. Only known read-only methods are
+// collapsed; SendTransaction, RequestAirdrop and methods sent with CallRaw
+// that the client does not know are always sent once per call.
+//
+// The shared request is sent with the context values of the caller that
+// started it, so values such as trace spans seen below the deduplication
+// layer are that caller's. It is cancelled only once every caller waiting on
+// it has given up.
+func WithDeduplication() ClientOption {
+	return func(c *Client) {
+		c.dedup = &dedup{calls: make(map[string]*flight)}
+	}
+}
+
+// dedup tracks calls in flight. It is shared by pointer, so sessions collapse
+// calls together with their client.
+type dedup struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+type flight struct {
+	done    chan struct{}
+	resp    *RawResponse
+	err     error
+	waiters int
+	cancel  context.CancelFunc
+}
+
+func (d *dedup) wrap(next Invoker) Invoker {
+	return func(ctx context.Context, req *Request) (*RawResponse, error) {
+		if !deduplicated[req.Method] {
+			return next(ctx, req)
+		}
+		key, err := requestKey(ctx, req)
+		if err != nil {
+			return next(ctx, req)
+		}
+
+		d.mu.Lock()
+		f, ok := d.calls[key]
+		if !ok {
+			callCtx, cancel := context.WithCancel(context.WithoutCancel(ctx))
+			f = &flight{done: make(chan struct{}), cancel: cancel}
+			d.calls[key] = f
+			go func() {
+				defer cancel()
+				f.resp, f.err = next(callCtx, req)
+				d.mu.Lock()
+				if d.calls[key] == f {
+					delete(d.calls, key)
+				}
+				d.mu.Unlock()
+				close(f.done)
+			}()
+		}
+		f.waiters++
+		d.mu.Unlock()
+
+		select {
+		case <-f.done:
+			if f.err != nil {
+				return nil, f.err
+			}
+			resp := *f.resp
+			resp.ID = req.ID
+			return &resp, nil
+		case <-ctx.Done():
+			d.mu.Lock()
+			f.waiters--
+			if f.waiters == 0 {
+				f.cancel()
+				if d.calls[key] == f {
+					delete(d.calls, key)
+				}
+			}
+			d.mu.Unlock()
+			return nil, ctx.Err()
+		}
+	}
+}
+
+// requestKey identifies a call by endpoint, method, headers, the response size
+// cap set on ctx and canonical params. Each param is round-tripped through
+// interface{} so object keys are sorted, and trailing empty configs are
+// dropped.
+func requestKey(ctx context.Context, req *Request) (string, error) {
+	params := make([]json.RawMessage, 0, len(req.Params))
+	for _, p := range req.Params {
+		raw, err := json.Marshal(p)
+		if err != nil {
+			return "", err
+		}
+		var v interface{}
+		if err := json.Unmarshal(raw, &v); err != nil {
+			return "", err
+		}
+		if raw, err = json.Marshal(v); err != nil {
+			return "", err
+		}
+		params = append(params, raw)
+	}
+	for len(params) > 0 {
+		last := params[len(params)-1]
+		if !bytes.Equal(last, []byte("{}")) && !bytes.Equal(last, []byte("null")) {
+			break
+		}
+		params = params[:len(params)-1]
+	}
+
+	var limit *int64
+	if n, ok := ctx.Value(maxResponseBytesKey{}).(int64); ok {
+		limit = &n
+	}
+	key, err := json.Marshal([]interface{}{req.Endpoint, req.Method, req.Header, limit, params})
+	if err != nil {
+		return "", err
+	}
+	return string(key), nil
+}
@@ -0,0 +1,61 @@
+package solana
+
+import "context"
+
+// callDefaults holds the values applied to configs the caller left unset.
+type callDefaults struct {
+	commitment     Commitment
+	minContextSlot Slot
+}
+
+type commitmentKey struct{}
+
+// WithCommitment returns a context that overrides the client's default
+// commitment for calls made with it. A commitment set explicitly on a call's
+// config still takes precedence.
+func WithCommitment(ctx context.Context, commitment Commitment) context.Context {
+	return context.WithValue(ctx, commitmentKey{}, commitment)
+}
+
+// confirmedOnlyMethods reject the processed commitment. A processed default
+// is raised to confirmed for them; one set on the call's config is sent as is.
+var confirmedOnlyMethods = map[string]bool{
+	"getBlock":                true,
+	"getBlocks":               true,
+	"getBlocksWithLimit":      true,
+	"getSignaturesForAddress": true,
+	"getTransaction":          true,
+}
+
+// defaults resolves the defaults for a single call to method from the client
+// and ctx.
+func (c *Client) defaults(ctx context.Context, method string) callDefaults {
+	d := callDefaults{commitment: c.defaultCommitment}
+	if commitment, ok := ctx.Value(commitmentKey{}).(Commitment); ok {
+		d.commitment = commitment
+	}
+	if d.commitment == CommitmentProcessed && confirmedOnlyMethods[method] {
+		d.commitment = CommitmentConfirmed
+	}
+	if c.session != nil {
+		d.minContextSlot = c.session.Slot()
+	}
+	return d
+}
+
+// withDefaults returns config with d applied, allocating one when config is
+// nil. The caller's config is copied rather than modified.
+func withDefaults[T any, PT interface {
+	*T
+	applyDefaults(callDefaults)
+}](d callDefaults, config PT) PT {
+	if d == (callDefaults{}) {
+		return config
+	}
+	cfg := PT(new(T))
+	if config != nil {
+		*cfg = *config
+	}
+	cfg.applyDefaults(d)
+	return cfg
+}
@@ -0,0 +1,145 @@
+package solana
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/bits"
+	"time"
+)
+
+// MinimumSlotsPerEpoch is the length of the first epoch of a cluster with
+// warmup. Each warmup epoch after it is twice as long as the one before,
+// until epochs reach SlotsPerEpoch at FirstNormalEpoch.
+const MinimumSlotsPerEpoch = 32
+
+// nominalSlotDuration is the slot time assumed when no performance samples
+// are available.
+const nominalSlotDuration = 400 * time.Millisecond
+
+var minimumSlotsPerEpochLog2 = bits.TrailingZeros64(MinimumSlotsPerEpoch)
+
+// Validate reports whether s can be used for slot arithmetic. Only a
+// malformed schedule fails it, and the methods below do not panic on one, but
+// their results for slots past FirstNormalSlot are then meaningless.
+func (s EpochSchedule) Validate() error {
+	if s.SlotsPerEpoch <= 0 {
+		return fmt.Errorf("epoch schedule has %d slots per epoch", s.SlotsPerEpoch)
+	}
+	return nil
+}
+
+// normalEpochLen is SlotsPerEpoch, or for a schedule that fails Validate the
+// largest length, so that dividing by it cannot panic.
+func (s EpochSchedule) normalEpochLen() int64 {
+	if s.SlotsPerEpoch <= 0 {
+		return math.MaxInt64
+	}
+	return s.SlotsPerEpoch
+}
+
+// EpochForSlot returns the epoch containing slot and the slot's index within
+// it.
+func (s EpochSchedule) EpochForSlot(slot Slot) (epoch, slotIndex int64) {
+	if slot < s.FirstNormalSlot {
+		// Warmup epoch n spans [(2^n - 1) * 32, (2^(n+1) - 1) * 32).
+		epoch = int64(bits.Len64(uint64(slot+MinimumSlotsPerEpoch+1)-1) - minimumSlotsPerEpochLog2 - 1)
+		epochLen := int64(1) << uint(epoch+int64(minimumSlotsPerEpochLog2))
+		return epoch, slot - (epochLen - MinimumSlotsPerEpoch)
+	}
+	normalSlotIndex := slot - s.FirstNormalSlot
+	return s.FirstNormalEpoch + normalSlotIndex/s.normalEpochLen(), normalSlotIndex % s.normalEpochLen()
+}
+
+// SlotsInEpoch returns the number of slots in epoch.
+func (s EpochSchedule) SlotsInEpoch(epoch int64) int64 {
+	if epoch < s.FirstNormalEpoch {
+		return int64(1) << uint(epoch+int64(minimumSlotsPerEpochLog2))
+	}
+	return s.SlotsPerEpoch
+}
+
+// FirstSlotInEpoch returns the first slot of epoch.
+func (s EpochSchedule) FirstSlotInEpoch(epoch int64) Slot {
+	if epoch <= s.FirstNormalEpoch {
+		return ((int64(1) << uint(epoch)) - 1) * MinimumSlotsPerEpoch
+	}
+	return (epoch-s.FirstNormalEpoch)*s.SlotsPerEpoch + s.FirstNormalSlot
+}
+
+// LastSlotInEpoch returns the last slot of epoch.
+func (s EpochSchedule) LastSlotInEpoch(epoch int64) Slot {
+	return s.FirstSlotInEpoch(epoch) + s.SlotsInEpoch(epoch) - 1
+}
+
+// LeaderScheduleEpoch returns the latest epoch whose leader schedule is known
+// at slot. Schedules are computed LeaderScheduleSlotOffset slots ahead, so
+// this is usually the epoch after the one containing slot.
+func (s EpochSchedule) LeaderScheduleEpoch(slot Slot) int64 {
+	if slot < s.FirstNormalSlot {
+		epoch, _ := s.EpochForSlot(slot)
+		return epoch + 1
+	}
+	newSlots := slot - s.FirstNormalSlot + s.LeaderScheduleSlotOffset
+	return s.FirstNormalEpoch + newSlots/s.normalEpochLen()
+}
+
+// EpochProgress describes how far the current epoch has advanced.
+type EpochProgress struct {
+	Epoch          int64
+	SlotIndex      int64
+	SlotsInEpoch   int64
+	SlotsRemaining int64
+
+	// Fraction is SlotIndex over SlotsInEpoch, from 0 to 1.
+	Fraction float64
+
+	// SlotDuration is the average slot time the estimate is based on, and
+	// Remaining the estimated time until the epoch ends.
+	SlotDuration time.Duration
+	Remaining    time.Duration
+}
+
+// EpochProgress estimates the time left in e's epoch from the slot rate in
+// samples, as returned by GetRecentPerformanceSamples. Without usable
+// samples the nominal 400ms slot time is assumed.
+func (e EpochInfo) EpochProgress(samples []PerformanceSample) EpochProgress {
+	p := EpochProgress{
+		Epoch:          e.Epoch,
+		SlotIndex:      e.SlotIndex,
+		SlotsInEpoch:   e.SlotsInEpoch,
+		SlotsRemaining: e.SlotsInEpoch - e.SlotIndex,
+		SlotDuration:   nominalSlotDuration,
+	}
+	if p.SlotsRemaining < 0 {
+		p.SlotsRemaining = 0
+	}
+	if e.SlotsInEpoch > 0 {
+		p.Fraction = float64(e.SlotIndex) / float64(e.SlotsInEpoch)
+	}
+
+	var secs, slots int64
+	for _, sample := range samples {
+		secs += sample.SamplePeriodSecs
+		slots += sample.NumSlots
+	}
+	if secs > 0 && slots > 0 {
+		p.SlotDuration = time.Duration(secs) * time.Second / time.Duration(slots)
+	}
+	p.Remaining = time.Duration(p.SlotsRemaining) * p.SlotDuration
+	return p
+}
+
+// GetEpochProgress fetches the current epoch and recent performance samples
+// and combines them with EpochInfo.EpochProgress.
+func (c *Client) GetEpochProgress(ctx context.Context, config *CommitmentConfig) (EpochProgress, error) {
+	info, err := c.GetEpochInfo(ctx, config)
+	if err != nil {
+		return EpochProgress{}, fmt.Errorf("get epoch info: %w", err)
+	}
+	samples, err := c.GetRecentPerformanceSamples(ctx, nil)
+	if err != nil {
+		return EpochProgress{}, fmt.Errorf("get performance samples: %w", err)
+	}
+	return info.EpochProgress(samples), nil
+}
@@ -0,0 +1,97 @@
+package solana
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strings"
+)
+
+// ErrResponseMismatch is matched by errors.Is for responses whose ID or
+// jsonrpc version does not belong to the request that was sent, as happens
+// when a misbehaving proxy crosses responses on a shared connection.
+var ErrResponseMismatch = errors.New("response does not match request")
+
+// ResponseMismatchError describes a response rejected with ErrResponseMismatch.
+// GotID is nil when the response carried no usable ID.
+type ResponseMismatchError struct {
+	Method  string
+	WantID  uint64
+	GotID   *uint64
+	Version string
+}
+
+func (e *ResponseMismatchError) Error() string {
+	got := "none"
+	if e.GotID != nil {
+		got = fmt.Sprint(*e.GotID)
+	}
+	return fmt.Sprintf("%s: %v: want id %d jsonrpc 2.0, got id %s jsonrpc %q", e.Method, ErrResponseMismatch, e.WantID, got, e.Version)
+}
+
+func (e *ResponseMismatchError) Unwrap() error {
+	return ErrResponseMismatch
+}
+
+// ErrHTTPStatus is matched by errors.Is when a server answers with a non-2xx
+// status and a body that is not a JSON-RPC error, as proxies and load
+// balancers do when they reject a request before it reaches a node.
+var ErrHTTPStatus = errors.New("unexpected HTTP status")
+
+// maxHTTPStatusBody caps how much of a rejected response's body is kept on
+// HTTPStatusError.
+const maxHTTPStatusBody = 512
+
+// HTTPStatusError describes a response rejected with ErrHTTPStatus. Body holds
+// the start of the response body, when it was read.
+type HTTPStatusError struct {
+	Method     string
+	StatusCode int
+	Body       string
+}
+
+func (e *HTTPStatusError) Error() string {
+	msg := fmt.Sprintf("%s: %v %d %s", e.Method, ErrHTTPStatus, e.StatusCode, http.StatusText(e.StatusCode))
+	if e.Body != "" {
+		msg += ": " + e.Body
+	}
+	return msg
+}
+
+func (e *HTTPStatusError) Unwrap() error {
+	return ErrHTTPStatus
+}
+
+// checkStatus returns an HTTPStatusError for a non-2xx response that carries
+// no JSON-RPC error, and nil otherwise.
+func checkStatus(req *Request, statusCode int, body []byte, resp *rpcResponse) error {
+	if statusCode >= 200 && statusCode < 300 || resp != nil && resp.Error != nil {
+		return nil
+	}
+	if len(body) > maxHTTPStatusBody {
+		body = body[:maxHTTPStatusBody]
+	}
+	return &HTTPStatusError{
+		Method:     req.Method,
+		StatusCode: statusCode,
+		Body:       strings.TrimSpace(string(body)),
+	}
+}
+
+// checkResponse verifies that resp answers req. A server that could not parse
+// the request replies with a null ID, so an error response without an ID is
+// let through to surface that error.
+func checkResponse(req *Request, resp *rpcResponse) error {
+	if resp.Error != nil && resp.ID == nil && resp.JsonRPC == "2.0" {
+		return nil
+	}
+	if resp.JsonRPC != "2.0" || resp.ID == nil || *resp.ID != req.ID {
+		return &ResponseMismatchError{
+			Method:  req.Method,
+			WantID:  req.ID,
+			GotID:   resp.ID,
+			Version: resp.JsonRPC,
+		}
+	}
+	return nil
+}
@@ -0,0 +1,151 @@
+package solana
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	// MaxProgramAccountsFilters is the most filters getProgramAccounts accepts.
+	MaxProgramAccountsFilters = 4
+
+	// MaxMemcmpBytes is the most bytes a memcmp filter may match, whatever
+	// their encoding.
+	MaxMemcmpBytes = 128
+)
+
+// AccountFilter is one entry of GetProgramAccountsConfig.Filters. Exactly one
+// of Memcmp, DataSize or TokenAccountState should be set; the helpers below
+// build each kind.
+type AccountFilter struct {
+	Memcmp *MemcmpFilter `json:"memcmp,omitempty"`
+
+	// DataSize is a pointer so that a filter for empty accounts, size 0, is
+	// still sent.
+	DataSize *int64 `json:"dataSize,omitempty"`
+
+	// TokenAccountState keeps only initialized SPL Token accounts. The server
+	// takes it as the bare string "tokenAccountState" rather than an object.
+	TokenAccountState bool `json:"-"`
+}
+
+// MemcmpFilter matches accounts whose data at Offset equals Bytes.
+type MemcmpFilter struct {
+	Offset   int64    `json:"offset"`
+	Bytes    string   `json:"bytes"`
+	Encoding Encoding `json:"encoding,omitempty"`
+}
+
+type accountFilterJSON AccountFilter
+
+func (f AccountFilter) MarshalJSON() ([]byte, error) {
+	if f.TokenAccountState {
+		return []byte(`"tokenAccountState"`), nil
+	}
+	return json.Marshal(accountFilterJSON(f))
+}
+
+func (f *AccountFilter) UnmarshalJSON(data []byte) error {
+	if string(data) == `"tokenAccountState"` {
+		*f = AccountFilter{TokenAccountState: true}
+		return nil
+	}
+	return json.Unmarshal(data, (*accountFilterJSON)(f))
+}
+
+// MemcmpBytes matches b at offset, base58-encoded. The server compares at
+// most MaxMemcmpBytes, so a longer b gives a filter that fails Validate.
+func MemcmpBytes(offset int64, b []byte) AccountFilter {
+	return AccountFilter{Memcmp: &MemcmpFilter{Offset: offset, Bytes: EncodeBase58(b), Encoding: EncodingBase58}}
+}
+
+// MemcmpPubkey matches the 32-byte public key pk at offset.
+func MemcmpPubkey(offset int64, pk Pubkey) AccountFilter {
+	return AccountFilter{Memcmp: &MemcmpFilter{Offset: offset, Bytes: pk, Encoding: EncodingBase58}}
+}
+
+// MemcmpU64 matches the little-endian encoding of v at offset.
+func MemcmpU64(offset int64, v uint64) AccountFilter {
+	var b [8]byte
+	binary.LittleEndian.PutUint64(b[:], v)
+	return MemcmpBytes(offset, b[:])
+}
+
+// DataSizeFilter matches accounts whose data is exactly size bytes long.
+func DataSizeFilter(size int64) AccountFilter {
+	return AccountFilter{DataSize: &size}
+}
+
+// TokenAccountStateFilter matches only initialized SPL Token accounts.
+func TokenAccountStateFilter() AccountFilter {
+	return AccountFilter{TokenAccountState: true}
+}
+
+// Validate checks the filter the way the server would, so malformed filters
+// fail before a request is sent.
+func (f AccountFilter) Validate() error {
+	kinds := 0
+	if f.Memcmp != nil {
+		kinds++
+	}
+	if f.DataSize != nil {
+		kinds++
+	}
+	if f.TokenAccountState {
+		kinds++
+	}
+	switch {
+	case kinds == 0:
+		return errors.New("filter sets none of memcmp, dataSize or tokenAccountState")
+	case kinds > 1:
+		return errors.New("filter sets more than one of memcmp, dataSize or tokenAccountState")
+	case f.DataSize != nil && *f.DataSize < 0:
+		return fmt.Errorf("negative dataSize %d", *f.DataSize)
+	case f.Memcmp != nil:
+		return f.Memcmp.Validate()
+	}
+	return nil
+}
+
+// Validate checks that Bytes decodes in its encoding to at most
+// MaxMemcmpBytes and that Offset is not negative.
+func (m MemcmpFilter) Validate() error {
+	if m.Offset < 0 {
+		return fmt.Errorf("memcmp: negative offset %d", m.Offset)
+	}
+
+	var b []byte
+	var err error
+	switch m.Encoding {
+	case "", EncodingBase58:
+		b, err = DecodeBase58(m.Bytes)
+	case EncodingBase64:
+		b, err = base64.StdEncoding.DecodeString(m.Bytes)
+	default:
+		return fmt.Errorf("memcmp: unsupported encoding %q", m.Encoding)
+	}
+	if err != nil {
+		return fmt.Errorf("memcmp: %w", err)
+	}
+	if len(b) > MaxMemcmpBytes {
+		return fmt.Errorf("memcmp: %d bytes exceeds the limit of %d", len(b), MaxMemcmpBytes)
+	}
+	return nil
+}
+
+// Validate checks the number of filters and each filter in turn.
+// GetProgramAccounts calls it before sending the request.
+func (c GetProgramAccountsConfig) Validate() error {
+	if len(c.Filters) > MaxProgramAccountsFilters {
+		return fmt.Errorf("%d filters exceeds the maximum of %d", len(c.Filters), MaxProgramAccountsFilters)
+	}
+	for i, f := range c.Filters {
+		if err := f.Validate(); err != nil {
+			return fmt.Errorf("filter %d: %w", i, err)
+		}
+	}
+	return nil
+}
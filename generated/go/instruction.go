@@ -0,0 +1,15 @@
+package solana
+
+// Instruction is a single program invocation to include in a transaction.
+type Instruction struct {
+	ProgramID Pubkey
+	Accounts  []AccountMeta
+	Data      []byte
+}
+
+// AccountMeta is an account an instruction reads or writes.
+type AccountMeta struct {
+	Pubkey     Pubkey
+	IsSigner   bool
+	IsWritable bool
+}
@@ -0,0 +1,117 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"reflect"
+)
+
+// Request is a single JSON-RPC call as seen by interceptors. Interceptors may
+// modify it before passing it on, for example to add headers or to point it
+// at a different endpoint.
+type Request struct {
+	ID       uint64
+	Endpoint string
+	Method   string
+	Params   []interface{}
+	Header   http.Header
+}
+
+// RawResponse is the JSON-RPC envelope of a call with its result still
+// undecoded. Error is set when the server answered with a JSON-RPC error;
+// transport failures are returned as the Invoker's error instead.
+type RawResponse struct {
+	ID         uint64
+	Result     json.RawMessage
+	Error      *RPCError
+	StatusCode int
+	Header     http.Header
+
+	// BodySize is the number of response body bytes read, zero for responses
+	// that did not come from the network.
+	BodySize int64
+}
+
+// Commitment returns the commitment the request asks for, or "" when none of
+// its params sets one and the node's default applies.
+func (r *Request) Commitment() Commitment {
+	for _, p := range r.Params {
+		v := reflect.Indirect(reflect.ValueOf(p))
+		if v.Kind() != reflect.Struct {
+			continue
+		}
+		for _, name := range []string{"Commitment", "PreflightCommitment"} {
+			if f := v.FieldByName(name); f.IsValid() && f.Type() == reflect.TypeOf(Commitment("")) && f.String() != "" {
+				return Commitment(f.String())
+			}
+		}
+	}
+	return ""
+}
+
+// ContextSlot returns the slot of the response context for results shaped
+// {context, value}.
+func (r *RawResponse) ContextSlot() (Slot, bool) {
+	if len(r.Result) == 0 || r.Result[0] != '{' {
+		return 0, false
+	}
+	var envelope struct {
+		Context *RpcContext `json:"context"`
+	}
+	if err := json.Unmarshal(r.Result, &envelope); err != nil || envelope.Context == nil {
+		return 0, false
+	}
+	return envelope.Context.Slot, true
+}
+
+// Invoker performs a call, either by sending it or by handing it to the next
+// interceptor in the chain.
+type Invoker func(ctx context.Context, req *Request) (*RawResponse, error)
+
+// Interceptor wraps every call made by a Client. It may inspect or modify req,
+// call next zero or more times, and inspect or replace the response. Typical
+// uses are logging, metrics, auth, caching and fault injection.
+type Interceptor func(ctx context.Context, req *Request, next Invoker) (*RawResponse, error)
+
+// WithInterceptors appends interceptors to the client's chain. The first
+// interceptor registered is the outermost and sees each call first.
+func WithInterceptors(interceptors ...Interceptor) ClientOption {
+	return func(c *Client) {
+		c.interceptors = append(c.interceptors, interceptors...)
+	}
+}
+
+// chainInterceptors composes interceptors around final, outermost first.
+func chainInterceptors(interceptors []Interceptor, final Invoker) Invoker {
+	next := final
+	for i := len(interceptors) - 1; i >= 0; i-- {
+		interceptor, inner := interceptors[i], next
+		next = func(ctx context.Context, req *Request) (*RawResponse, error) {
+			return interceptor(ctx, req, inner)
+		}
+	}
+	return next
+}
+
+// transport returns the innermost invoker: the HTTP round trip, plus request
+// logging when a logger is configured, rate limiting when limits are set, and
+// deduplication and caching when enabled. Limits wrap logging so logged
+// durations exclude time spent waiting, and deduplication and the cache wrap
+// limits so collapsed calls and cache hits cost at most one request.
+func (c *Client) transport() Invoker {
+	next := c.roundTrip
+	if c.logger != nil {
+		next = c.logRequests(next)
+	}
+	if c.limiter != nil {
+		next = c.limiter.wrap(next)
+	}
+	if c.dedup != nil {
+		next = c.dedup.wrap(next)
+	}
+	if c.cache != nil {
+		next = c.cache.wrap(next)
+	}
+	return next
+}
@@ -0,0 +1,208 @@
+package solana
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// MaxSlotLeaders is the most leaders getSlotLeaders returns per request.
+const MaxSlotLeaders = 5000
+
+// ErrLeaderScheduleUnavailable is matched by errors.Is when the node has not
+// computed the leader schedule for a requested slot yet.
+var ErrLeaderScheduleUnavailable = errors.New("leader schedule not available")
+
+// SlotLeader is the validator identity scheduled to produce a slot.
+type SlotLeader struct {
+	Slot   Slot
+	Leader Pubkey
+}
+
+// LeaderScheduleCache answers leader schedule questions in absolute slots.
+// Schedules are fetched once per epoch with GetLeaderSchedule and indexed by
+// slot; epochs that have ended are dropped as calls observe a new epoch.
+// It is safe for concurrent use.
+type LeaderScheduleCache struct {
+	client RPC
+
+	mu       sync.Mutex
+	schedule *EpochSchedule
+	epochs   map[int64]*epochLeaders
+}
+
+// epochLeaders is one epoch's schedule, indexed by slot within the epoch.
+type epochLeaders struct {
+	firstSlot Slot
+	leaders   []Pubkey
+}
+
+// NewLeaderScheduleCache returns an empty cache over c, typically a Client or
+// Session. Nothing is fetched until the first call.
+func NewLeaderScheduleCache(c RPC) *LeaderScheduleCache {
+	return &LeaderScheduleCache{client: c, epochs: make(map[int64]*epochLeaders)}
+}
+
+// LeaderAt returns the leader of slot.
+func (l *LeaderScheduleCache) LeaderAt(ctx context.Context, slot Slot) (Pubkey, error) {
+	schedule, err := l.epochSchedule(ctx)
+	if err != nil {
+		return "", err
+	}
+	epoch, index := schedule.EpochForSlot(slot)
+	leaders, err := l.leaders(ctx, epoch)
+	if err != nil {
+		return "", err
+	}
+	if leaders == nil || index >= int64(len(leaders.leaders)) {
+		return "", fmt.Errorf("slot %d: %w", slot, ErrLeaderScheduleUnavailable)
+	}
+	return leaders.leaders[index], nil
+}
+
+// NextSlotsFor returns up to n upcoming slots led by identity, starting at
+// the current slot. Fewer are returned when the known schedules run out.
+func (l *LeaderScheduleCache) NextSlotsFor(ctx context.Context, identity Pubkey, n int) ([]Slot, error) {
+	info, err := l.currentEpoch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	var slots []Slot
+	for epoch, index := info.Epoch, info.SlotIndex; len(slots) < n; epoch, index = epoch+1, 0 {
+		leaders, err := l.leaders(ctx, epoch)
+		if err != nil {
+			return nil, err
+		}
+		if leaders == nil {
+			break
+		}
+		for ; index < int64(len(leaders.leaders)) && len(slots) < n; index++ {
+			if leaders.leaders[index] == identity {
+				slots = append(slots, leaders.firstSlot+index)
+			}
+		}
+	}
+	return slots, nil
+}
+
+// UpcomingLeaders returns the leaders of the next k slots, starting at the
+// current slot. Slots beyond the cached schedules are looked up with
+// GetSlotLeaders.
+func (l *LeaderScheduleCache) UpcomingLeaders(ctx context.Context, k int) ([]SlotLeader, error) {
+	info, err := l.currentEpoch(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	out := make([]SlotLeader, 0, k)
+	for epoch, index := info.Epoch, info.SlotIndex; len(out) < k; epoch, index = epoch+1, 0 {
+		leaders, err := l.leaders(ctx, epoch)
+		if err != nil {
+			return nil, err
+		}
+		if leaders == nil {
+			break
+		}
+		for ; index < int64(len(leaders.leaders)) && len(out) < k; index++ {
+			out = append(out, SlotLeader{Slot: leaders.firstSlot + index, Leader: leaders.leaders[index]})
+		}
+	}
+
+	for len(out) < k {
+		start := info.AbsoluteSlot + int64(len(out))
+		limit := k - len(out)
+		if limit > MaxSlotLeaders {
+			limit = MaxSlotLeaders
+		}
+		pubkeys, err := l.client.GetSlotLeaders(ctx, start, int64(limit))
+		if err != nil {
+			return nil, fmt.Errorf("get slot leaders from %d: %w", start, err)
+		}
+		if len(pubkeys) == 0 {
+			break
+		}
+		for i, pk := range pubkeys {
+			out = append(out, SlotLeader{Slot: start + int64(i), Leader: pk})
+		}
+	}
+	return out, nil
+}
+
+func (l *LeaderScheduleCache) epochSchedule(ctx context.Context) (EpochSchedule, error) {
+	l.mu.Lock()
+	schedule := l.schedule
+	l.mu.Unlock()
+	if schedule != nil {
+		return *schedule, nil
+	}
+
+	fetched, err := l.client.GetEpochSchedule(ctx)
+	if err != nil {
+		return EpochSchedule{}, fmt.Errorf("get epoch schedule: %w", err)
+	}
+	if err := fetched.Validate(); err != nil {
+		return EpochSchedule{}, err
+	}
+	l.mu.Lock()
+	l.schedule = &fetched
+	l.mu.Unlock()
+	return fetched, nil
+}
+
+// currentEpoch fetches the current epoch and drops schedules of epochs that
+// have ended.
+func (l *LeaderScheduleCache) currentEpoch(ctx context.Context) (EpochInfo, error) {
+	info, err := l.client.GetEpochInfo(ctx, nil)
+	if err != nil {
+		return EpochInfo{}, fmt.Errorf("get epoch info: %w", err)
+	}
+
+	l.mu.Lock()
+	for epoch := range l.epochs {
+		if epoch < info.Epoch {
+			delete(l.epochs, epoch)
+		}
+	}
+	l.mu.Unlock()
+	return info, nil
+}
+
+// leaders returns epoch's schedule, fetching it if needed, or nil when the
+// node does not have it yet.
+func (l *LeaderScheduleCache) leaders(ctx context.Context, epoch int64) (*epochLeaders, error) {
+	l.mu.Lock()
+	cached, ok := l.epochs[epoch]
+	l.mu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	schedule, err := l.epochSchedule(ctx)
+	if err != nil {
+		return nil, err
+	}
+	firstSlot := schedule.FirstSlotInEpoch(epoch)
+	byIdentity, err := l.client.GetLeaderSchedule(ctx, &firstSlot, nil)
+	if err != nil {
+		return nil, fmt.Errorf("get leader schedule for epoch %d: %w", epoch, err)
+	}
+	if byIdentity == nil {
+		return nil, nil
+	}
+
+	leaders := &epochLeaders{firstSlot: firstSlot, leaders: make([]Pubkey, schedule.SlotsInEpoch(epoch))}
+	for identity, indices := range byIdentity {
+		for _, index := range indices {
+			if index >= 0 && index < int64(len(leaders.leaders)) {
+				leaders.leaders[index] = identity
+			}
+		}
+	}
+
+	l.mu.Lock()
+	l.epochs[epoch] = leaders
+	l.mu.Unlock()
+	return leaders, nil
+}
@@ -0,0 +1,110 @@
+package solana
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sync/atomic"
+	"time"
+)
+
+// ErrResponseTooLarge is matched by errors.Is when a response body exceeds
+// the configured size limit.
+var ErrResponseTooLarge = errors.New("response too large")
+
+// ErrBodyReadTimeout is matched by errors.Is when a response body is not
+// read in full within the client's body read timeout.
+var ErrBodyReadTimeout = errors.New("response body read timed out")
+
+// ResponseTooLargeError describes a response rejected with ErrResponseTooLarge.
+type ResponseTooLargeError struct {
+	Method string
+	Limit  int64
+}
+
+func (e *ResponseTooLargeError) Error() string {
+	return fmt.Sprintf("%s: %v: limit is %d bytes", e.Method, ErrResponseTooLarge, e.Limit)
+}
+
+func (e *ResponseTooLargeError) Unwrap() error {
+	return ErrResponseTooLarge
+}
+
+// WithDefaultMaxResponseBytes caps the size of every response body. Larger
+// responses fail with ErrResponseTooLarge instead of being read into memory.
+// WithMaxResponseBytes overrides the cap for individual calls.
+func WithDefaultMaxResponseBytes(n int64) ClientOption {
+	return func(c *Client) {
+		c.maxResponseBytes = n
+	}
+}
+
+// WithBodyReadTimeout bounds how long reading a response body may take once
+// its headers have arrived, protecting against endpoints that trickle bytes.
+// Use the HTTP client's own timeouts to bound the wait for headers.
+func WithBodyReadTimeout(d time.Duration) ClientOption {
+	return func(c *Client) {
+		c.bodyReadTimeout = d
+	}
+}
+
+type maxResponseBytesKey struct{}
+
+// WithMaxResponseBytes returns a context that overrides the client's response
+// size cap for calls made with it. n <= 0 removes the cap.
+func WithMaxResponseBytes(ctx context.Context, n int64) context.Context {
+	return context.WithValue(ctx, maxResponseBytesKey{}, n)
+}
+
+func (c *Client) responseLimit(ctx context.Context) int64 {
+	if n, ok := ctx.Value(maxResponseBytesKey{}).(int64); ok {
+		return n
+	}
+	return c.maxResponseBytes
+}
+
+// readBody reads and decompresses resp's body within the size limit and body
+// read timeout.
+// cancel must cancel the request's context, which aborts a stalled read.
+func (c *Client) readBody(ctx context.Context, r *Request, resp *http.Response, cancel context.CancelFunc) ([]byte, error) {
+	limit := c.responseLimit(ctx)
+	if limit > 0 && resp.ContentLength > limit {
+		return nil, &ResponseTooLargeError{Method: r.Method, Limit: limit}
+	}
+
+	var timedOut atomic.Bool
+	if c.bodyReadTimeout > 0 {
+		timer := time.AfterFunc(c.bodyReadTimeout, func() {
+			timedOut.Store(true)
+			cancel()
+		})
+		defer timer.Stop()
+	}
+
+	// The limit applies to the decompressed body, which is what gets held in
+	// memory.
+	decoded, err := c.compression.decode(resp)
+	if err != nil {
+		return nil, err
+	}
+	defer decoded.Close()
+	var body io.Reader = decoded
+	if limit > 0 {
+		body = io.LimitReader(body, limit+1)
+	}
+
+	b, err := io.ReadAll(body)
+	if err != nil {
+		if timedOut.Load() {
+			return nil, fmt.Errorf("read response: %w after %v", ErrBodyReadTimeout, c.bodyReadTimeout)
+		}
+		return nil, fmt.Errorf("read response: %w", err)
+	}
+	if limit > 0 && int64(len(b)) > limit {
+		return nil, &ResponseTooLargeError{Method: r.Method, Limit: limit}
+	}
+	c.compression.record(decoded, int64(len(b)))
+	return b, nil
+}
@@ -0,0 +1,132 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log/slog"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const redacted = "REDACTED"
+
+// WithLogger logs every request the client sends to l at debug level, with
+// its method, request ID, endpoint, duration, HTTP status, response size and
+// error. API keys embedded in the endpoint URL are redacted.
+func WithLogger(l *slog.Logger) ClientOption {
+	return func(c *Client) {
+		c.logger = l
+	}
+}
+
+// WithLogBodies additionally logs request params and response results,
+// truncated to limit bytes each. It has no effect without WithLogger.
+func WithLogBodies(limit int) ClientOption {
+	return func(c *Client) {
+		c.logBodyLimit = limit
+	}
+}
+
+func (c *Client) logRequests(next Invoker) Invoker {
+	logger, limit := c.logger, c.logBodyLimit
+	return func(ctx context.Context, req *Request) (*RawResponse, error) {
+		if !logger.Enabled(ctx, slog.LevelDebug) {
+			return next(ctx, req)
+		}
+
+		start := time.Now()
+		resp, err := next(ctx, req)
+
+		attrs := []slog.Attr{
+			slog.String("method", req.Method),
+			slog.Uint64("request_id", req.ID),
+			slog.String("endpoint", redactEndpoint(req.Endpoint)),
+			slog.Duration("duration", time.Since(start)),
+		}
+		if limit > 0 {
+			params, _ := json.Marshal(req.Params)
+			attrs = append(attrs, slog.String("params", truncate(params, limit)))
+		}
+		if resp != nil {
+			attrs = append(attrs,
+				slog.Int("status", resp.StatusCode),
+				slog.Int64("response_bytes", resp.BodySize))
+			if resp.Error != nil {
+				attrs = append(attrs, slog.Any("error", resp.Error))
+			} else if limit > 0 {
+				attrs = append(attrs, slog.String("result", truncate(resp.Result, limit)))
+			}
+		}
+		if err != nil {
+			attrs = append(attrs, slog.Any("error", err))
+		}
+		logger.LogAttrs(ctx, slog.LevelDebug, "solana rpc call", attrs...)
+
+		return resp, err
+	}
+}
+
+func truncate(b []byte, limit int) string {
+	if len(b) <= limit {
+		return string(b)
+	}
+	return fmt.Sprintf("%s...(%d bytes total)", b[:limit], len(b))
+}
+
+// redactEndpoint strips credentials from an endpoint URL: user info, every
+// query value and any path segment long enough to be an API key, as used by
+// most hosted RPC providers.
+func redactEndpoint(endpoint string) string {
+	u, err := url.Parse(endpoint)
+	if err != nil {
+		return redacted
+	}
+
+	if u.User != nil {
+		u.User = url.User(redacted)
+	}
+
+	if u.RawQuery != "" {
+		q := u.Query()
+		for k := range q {
+			q[k] = []string{redacted}
+		}
+		u.RawQuery = q.Encode()
+	}
+
+	segments := strings.Split(u.Path, "/")
+	for i, seg := range segments {
+		if looksLikeSecret(seg) {
+			segments[i] = redacted
+		}
+	}
+	u.Path = strings.Join(segments, "/")
+	u.RawPath = ""
+
+	return u.String()
+}
+
+func looksLikeSecret(s string) bool {
+	if len(s) < 16 {
+		return false
+	}
+	for _, r := range s {
+		if !(r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_') {
+			return false
+		}
+	}
+	return true
+}
+
+// redactURLError removes credentials from the URL net/http includes in its
+// errors, so wrapped transport errors can be logged safely.
+func redactURLError(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = redactEndpoint(urlErr.URL)
+	}
+	return err
+}
@@ -0,0 +1,591 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+)
+
+// ErrNotMocked is returned by MockRPC methods whose function field is unset.
+var ErrNotMocked = errors.New("method not mocked")
+
+// MockCall records a single call made on a MockRPC.
+type MockCall struct {
+	Method string
+	Args   []interface{}
+}
+
+// MockRPC implements RPC without a network. Each method calls the matching
+// function field, or returns ErrNotMocked when it is nil, and every call is
+// recorded for later inspection with Calls.
+type MockRPC struct {
+	CallFunc func(ctx context.Context, method string, params []interface{}, result interface{}) error
+	CallRawFunc func(ctx context.Context, method string, params []interface{}) (json.RawMessage, error)
+	GetAccountInfoFunc func(ctx context.Context, pubkey Pubkey, config *GetAccountInfoConfig) (AccountInfoResponse, error)
+	GetBalanceFunc func(ctx context.Context, pubkey Pubkey, config *CommitmentConfig) (RpcResponseU64, error)
+	GetBlockFunc func(ctx context.Context, slot Slot, config *GetBlockConfig) (Block, error)
+	GetBlockCommitmentFunc func(ctx context.Context, slot Slot) (BlockCommitment, error)
+	GetBlockHeightFunc func(ctx context.Context, config *CommitmentConfig) (int64, error)
+	GetBlockProductionFunc func(ctx context.Context, config *GetBlockProductionConfig) (BlockProduction, error)
+	GetBlockTimeFunc func(ctx context.Context, slot Slot) (int64, error)
+	GetBlocksFunc func(ctx context.Context, startSlot Slot, endSlot *Slot, config *CommitmentConfig) ([]Slot, error)
+	GetBlocksWithLimitFunc func(ctx context.Context, startSlot Slot, limit int64, config *CommitmentConfig) ([]Slot, error)
+	GetClusterNodesFunc func(ctx context.Context) ([]ClusterNode, error)
+	GetEpochInfoFunc func(ctx context.Context, config *CommitmentConfig) (EpochInfo, error)
+	GetEpochScheduleFunc func(ctx context.Context) (EpochSchedule, error)
+	GetFeeForMessageFunc func(ctx context.Context, message string, config *CommitmentConfig) (RpcResponseU64, error)
+	GetFirstAvailableBlockFunc func(ctx context.Context) (Slot, error)
+	GetGenesisHashFunc func(ctx context.Context) (Hash, error)
+	GetHealthFunc func(ctx context.Context) (string, error)
+	GetHighestSnapshotSlotFunc func(ctx context.Context) (SnapshotSlotInfo, error)
+	GetIdentityFunc func(ctx context.Context) (map[string]interface{}, error)
+	GetInflationGovernorFunc func(ctx context.Context, config *CommitmentConfig) (InflationGovernor, error)
+	GetInflationRateFunc func(ctx context.Context) (InflationRate, error)
+	GetInflationRewardFunc func(ctx context.Context, addresses []Pubkey, config *GetInflationRewardConfig) ([]InflationReward, error)
+	GetLargestAccountsFunc func(ctx context.Context, config *GetLargestAccountsConfig) (RpcResponseLargestAccounts, error)
+	GetLatestBlockhashFunc func(ctx context.Context, config *CommitmentConfig) (LatestBlockhashResponse, error)
+	GetLeaderScheduleFunc func(ctx context.Context, slot *Slot, config *GetLeaderScheduleConfig) (LeaderSchedule, error)
+	GetMaxRetransmitSlotFunc func(ctx context.Context) (Slot, error)
+	GetMaxShredInsertSlotFunc func(ctx context.Context) (Slot, error)
+	GetMinimumBalanceForRentExemptionFunc func(ctx context.Context, dataLength int64, config *CommitmentConfig) (int64, error)
+	GetMultipleAccountsFunc func(ctx context.Context, pubkeys []Pubkey, config *GetAccountInfoConfig) (MultipleAccountsResponse, error)
+	GetProgramAccountsFunc func(ctx context.Context, programId Pubkey, config *GetProgramAccountsConfig) ([]ProgramAccount, error)
+	GetRecentPerformanceSamplesFunc func(ctx context.Context, limit *int64) ([]PerformanceSample, error)
+	GetRecentPrioritizationFeesFunc func(ctx context.Context, addresses *[]Pubkey) ([]PrioritizationFee, error)
+	GetSignatureStatusesFunc func(ctx context.Context, signatures []Signature, config *GetSignatureStatusesConfig) (SignatureStatusesResponse, error)
+	GetSignaturesForAddressFunc func(ctx context.Context, address Pubkey, config *GetSignaturesForAddressConfig) ([]SignatureInfo, error)
+	GetSlotFunc func(ctx context.Context, config *CommitmentConfig) (Slot, error)
+	GetSlotLeaderFunc func(ctx context.Context, config *CommitmentConfig) (Pubkey, error)
+	GetSlotLeadersFunc func(ctx context.Context, startSlot Slot, limit int64) ([]Pubkey, error)
+	GetStakeMinimumDelegationFunc func(ctx context.Context, config *CommitmentConfig) (RpcResponseU64, error)
+	GetSupplyFunc func(ctx context.Context, config *GetSupplyConfig) (SupplyResponse, error)
+	GetTokenAccountBalanceFunc func(ctx context.Context, pubkey Pubkey, config *CommitmentConfig) (TokenBalanceResponse, error)
+	GetTokenAccountsByDelegateFunc func(ctx context.Context, delegate Pubkey, filter TokenAccountsFilter, config *GetTokenAccountsConfig) (TokenAccountsResponse, error)
+	GetTokenAccountsByOwnerFunc func(ctx context.Context, owner Pubkey, filter TokenAccountsFilter, config *GetTokenAccountsConfig) (TokenAccountsResponse, error)
+	GetTokenLargestAccountsFunc func(ctx context.Context, mint Pubkey, config *CommitmentConfig) (TokenLargestAccountsResponse, error)
+	GetTokenSupplyFunc func(ctx context.Context, mint Pubkey, config *CommitmentConfig) (TokenBalanceResponse, error)
+	GetTransactionFunc func(ctx context.Context, signature Signature, config *GetTransactionConfig) (TransactionResponse, error)
+	GetTransactionCountFunc func(ctx context.Context, config *CommitmentConfig) (int64, error)
+	GetVersionFunc func(ctx context.Context) (Version, error)
+	GetVoteAccountsFunc func(ctx context.Context, config *GetVoteAccountsConfig) (VoteAccountsResponse, error)
+	IsBlockhashValidFunc func(ctx context.Context, blockhash Hash, config *CommitmentConfig) (RpcResponseBool, error)
+	MinimumLedgerSlotFunc func(ctx context.Context) (Slot, error)
+	RequestAirdropFunc func(ctx context.Context, pubkey Pubkey, lamports int64, config *CommitmentConfig) (Signature, error)
+	SendTransactionFunc func(ctx context.Context, transaction string, config *SendTransactionConfig) (Signature, error)
+	SimulateTransactionFunc func(ctx context.Context, transaction string, config *SimulateTransactionConfig) (SimulateTransactionResponse, error)
+
+	mu    sync.Mutex
+	calls []MockCall
+}
+
+var _ RPC = (*MockRPC)(nil)
+
+// Calls returns the calls made so far, in order. The context argument is omitted.
+func (m *MockRPC) Calls() []MockCall {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]MockCall(nil), m.calls...)
+}
+
+// CallsTo returns the recorded calls to the named method, such as "GetBalance".
+func (m *MockRPC) CallsTo(method string) []MockCall {
+	var out []MockCall
+	for _, call := range m.Calls() {
+		if call.Method == method {
+			out = append(out, call)
+		}
+	}
+	return out
+}
+
+func (m *MockRPC) record(method string, args ...interface{}) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.calls = append(m.calls, MockCall{Method: method, Args: args})
+}
+
+func (m *MockRPC) Call(ctx context.Context, method string, params []interface{}, result interface{}) error {
+	m.record("Call", method, params, result)
+	if m.CallFunc == nil {
+		return fmt.Errorf("Call: %w", ErrNotMocked)
+	}
+	return m.CallFunc(ctx, method, params, result)
+}
+
+func (m *MockRPC) CallRaw(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
+	m.record("CallRaw", method, params)
+	if m.CallRawFunc == nil {
+		return nil, fmt.Errorf("CallRaw: %w", ErrNotMocked)
+	}
+	return m.CallRawFunc(ctx, method, params)
+}
+
+func (m *MockRPC) GetAccountInfo(ctx context.Context, pubkey Pubkey, config *GetAccountInfoConfig) (AccountInfoResponse, error) {
+	m.record("GetAccountInfo", pubkey, config)
+	if m.GetAccountInfoFunc == nil {
+		var zero AccountInfoResponse
+		return zero, fmt.Errorf("GetAccountInfo: %w", ErrNotMocked)
+	}
+	return m.GetAccountInfoFunc(ctx, pubkey, config)
+}
+
+func (m *MockRPC) GetBalance(ctx context.Context, pubkey Pubkey, config *CommitmentConfig) (RpcResponseU64, error) {
+	m.record("GetBalance", pubkey, config)
+	if m.GetBalanceFunc == nil {
+		var zero RpcResponseU64
+		return zero, fmt.Errorf("GetBalance: %w", ErrNotMocked)
+	}
+	return m.GetBalanceFunc(ctx, pubkey, config)
+}
+
+func (m *MockRPC) GetBlock(ctx context.Context, slot Slot, config *GetBlockConfig) (Block, error) {
+	m.record("GetBlock", slot, config)
+	if m.GetBlockFunc == nil {
+		var zero Block
+		return zero, fmt.Errorf("GetBlock: %w", ErrNotMocked)
+	}
+	return m.GetBlockFunc(ctx, slot, config)
+}
+
+func (m *MockRPC) GetBlockCommitment(ctx context.Context, slot Slot) (BlockCommitment, error) {
+	m.record("GetBlockCommitment", slot)
+	if m.GetBlockCommitmentFunc == nil {
+		var zero BlockCommitment
+		return zero, fmt.Errorf("GetBlockCommitment: %w", ErrNotMocked)
+	}
+	return m.GetBlockCommitmentFunc(ctx, slot)
+}
+
+func (m *MockRPC) GetBlockHeight(ctx context.Context, config *CommitmentConfig) (int64, error) {
+	m.record("GetBlockHeight", config)
+	if m.GetBlockHeightFunc == nil {
+		var zero int64
+		return zero, fmt.Errorf("GetBlockHeight: %w", ErrNotMocked)
+	}
+	return m.GetBlockHeightFunc(ctx, config)
+}
+
+func (m *MockRPC) GetBlockProduction(ctx context.Context, config *GetBlockProductionConfig) (BlockProduction, error) {
+	m.record("GetBlockProduction", config)
+	if m.GetBlockProductionFunc == nil {
+		var zero BlockProduction
+		return zero, fmt.Errorf("GetBlockProduction: %w", ErrNotMocked)
+	}
+	return m.GetBlockProductionFunc(ctx, config)
+}
+
+func (m *MockRPC) GetBlockTime(ctx context.Context, slot Slot) (int64, error) {
+	m.record("GetBlockTime", slot)
+	if m.GetBlockTimeFunc == nil {
+		var zero int64
+		return zero, fmt.Errorf("GetBlockTime: %w", ErrNotMocked)
+	}
+	return m.GetBlockTimeFunc(ctx, slot)
+}
+
+func (m *MockRPC) GetBlocks(ctx context.Context, startSlot Slot, endSlot *Slot, config *CommitmentConfig) ([]Slot, error) {
+	m.record("GetBlocks", startSlot, endSlot, config)
+	if m.GetBlocksFunc == nil {
+		var zero []Slot
+		return zero, fmt.Errorf("GetBlocks: %w", ErrNotMocked)
+	}
+	return m.GetBlocksFunc(ctx, startSlot, endSlot, config)
+}
+
+func (m *MockRPC) GetBlocksWithLimit(ctx context.Context, startSlot Slot, limit int64, config *CommitmentConfig) ([]Slot, error) {
+	m.record("GetBlocksWithLimit", startSlot, limit, config)
+	if m.GetBlocksWithLimitFunc == nil {
+		var zero []Slot
+		return zero, fmt.Errorf("GetBlocksWithLimit: %w", ErrNotMocked)
+	}
+	return m.GetBlocksWithLimitFunc(ctx, startSlot, limit, config)
+}
+
+func (m *MockRPC) GetClusterNodes(ctx context.Context) ([]ClusterNode, error) {
+	m.record("GetClusterNodes")
+	if m.GetClusterNodesFunc == nil {
+		var zero []ClusterNode
+		return zero, fmt.Errorf("GetClusterNodes: %w", ErrNotMocked)
+	}
+	return m.GetClusterNodesFunc(ctx)
+}
+
+func (m *MockRPC) GetEpochInfo(ctx context.Context, config *CommitmentConfig) (EpochInfo, error) {
+	m.record("GetEpochInfo", config)
+	if m.GetEpochInfoFunc == nil {
+		var zero EpochInfo
+		return zero, fmt.Errorf("GetEpochInfo: %w", ErrNotMocked)
+	}
+	return m.GetEpochInfoFunc(ctx, config)
+}
+
+func (m *MockRPC) GetEpochSchedule(ctx context.Context) (EpochSchedule, error) {
+	m.record("GetEpochSchedule")
+	if m.GetEpochScheduleFunc == nil {
+		var zero EpochSchedule
+		return zero, fmt.Errorf("GetEpochSchedule: %w", ErrNotMocked)
+	}
+	return m.GetEpochScheduleFunc(ctx)
+}
+
+func (m *MockRPC) GetFeeForMessage(ctx context.Context, message string, config *CommitmentConfig) (RpcResponseU64, error) {
+	m.record("GetFeeForMessage", message, config)
+	if m.GetFeeForMessageFunc == nil {
+		var zero RpcResponseU64
+		return zero, fmt.Errorf("GetFeeForMessage: %w", ErrNotMocked)
+	}
+	return m.GetFeeForMessageFunc(ctx, message, config)
+}
+
+func (m *MockRPC) GetFirstAvailableBlock(ctx context.Context) (Slot, error) {
+	m.record("GetFirstAvailableBlock")
+	if m.GetFirstAvailableBlockFunc == nil {
+		var zero Slot
+		return zero, fmt.Errorf("GetFirstAvailableBlock: %w", ErrNotMocked)
+	}
+	return m.GetFirstAvailableBlockFunc(ctx)
+}
+
+func (m *MockRPC) GetGenesisHash(ctx context.Context) (Hash, error) {
+	m.record("GetGenesisHash")
+	if m.GetGenesisHashFunc == nil {
+		var zero Hash
+		return zero, fmt.Errorf("GetGenesisHash: %w", ErrNotMocked)
+	}
+	return m.GetGenesisHashFunc(ctx)
+}
+
+func (m *MockRPC) GetHealth(ctx context.Context) (string, error) {
+	m.record("GetHealth")
+	if m.GetHealthFunc == nil {
+		var zero string
+		return zero, fmt.Errorf("GetHealth: %w", ErrNotMocked)
+	}
+	return m.GetHealthFunc(ctx)
+}
+
+func (m *MockRPC) GetHighestSnapshotSlot(ctx context.Context) (SnapshotSlotInfo, error) {
+	m.record("GetHighestSnapshotSlot")
+	if m.GetHighestSnapshotSlotFunc == nil {
+		var zero SnapshotSlotInfo
+		return zero, fmt.Errorf("GetHighestSnapshotSlot: %w", ErrNotMocked)
+	}
+	return m.GetHighestSnapshotSlotFunc(ctx)
+}
+
+func (m *MockRPC) GetIdentity(ctx context.Context) (map[string]interface{}, error) {
+	m.record("GetIdentity")
+	if m.GetIdentityFunc == nil {
+		var zero map[string]interface{}
+		return zero, fmt.Errorf("GetIdentity: %w", ErrNotMocked)
+	}
+	return m.GetIdentityFunc(ctx)
+}
+
+func (m *MockRPC) GetInflationGovernor(ctx context.Context, config *CommitmentConfig) (InflationGovernor, error) {
+	m.record("GetInflationGovernor", config)
+	if m.GetInflationGovernorFunc == nil {
+		var zero InflationGovernor
+		return zero, fmt.Errorf("GetInflationGovernor: %w", ErrNotMocked)
+	}
+	return m.GetInflationGovernorFunc(ctx, config)
+}
+
+func (m *MockRPC) GetInflationRate(ctx context.Context) (InflationRate, error) {
+	m.record("GetInflationRate")
+	if m.GetInflationRateFunc == nil {
+		var zero InflationRate
+		return zero, fmt.Errorf("GetInflationRate: %w", ErrNotMocked)
+	}
+	return m.GetInflationRateFunc(ctx)
+}
+
+func (m *MockRPC) GetInflationReward(ctx context.Context, addresses []Pubkey, config *GetInflationRewardConfig) ([]InflationReward, error) {
+	m.record("GetInflationReward", addresses, config)
+	if m.GetInflationRewardFunc == nil {
+		var zero []InflationReward
+		return zero, fmt.Errorf("GetInflationReward: %w", ErrNotMocked)
+	}
+	return m.GetInflationRewardFunc(ctx, addresses, config)
+}
+
+func (m *MockRPC) GetLargestAccounts(ctx context.Context, config *GetLargestAccountsConfig) (RpcResponseLargestAccounts, error) {
+	m.record("GetLargestAccounts", config)
+	if m.GetLargestAccountsFunc == nil {
+		var zero RpcResponseLargestAccounts
+		return zero, fmt.Errorf("GetLargestAccounts: %w", ErrNotMocked)
+	}
+	return m.GetLargestAccountsFunc(ctx, config)
+}
+
+func (m *MockRPC) GetLatestBlockhash(ctx context.Context, config *CommitmentConfig) (LatestBlockhashResponse, error) {
+	m.record("GetLatestBlockhash", config)
+	if m.GetLatestBlockhashFunc == nil {
+		var zero LatestBlockhashResponse
+		return zero, fmt.Errorf("GetLatestBlockhash: %w", ErrNotMocked)
+	}
+	return m.GetLatestBlockhashFunc(ctx, config)
+}
+
+func (m *MockRPC) GetLeaderSchedule(ctx context.Context, slot *Slot, config *GetLeaderScheduleConfig) (LeaderSchedule, error) {
+	m.record("GetLeaderSchedule", slot, config)
+	if m.GetLeaderScheduleFunc == nil {
+		var zero LeaderSchedule
+		return zero, fmt.Errorf("GetLeaderSchedule: %w", ErrNotMocked)
+	}
+	return m.GetLeaderScheduleFunc(ctx, slot, config)
+}
+
+func (m *MockRPC) GetMaxRetransmitSlot(ctx context.Context) (Slot, error) {
+	m.record("GetMaxRetransmitSlot")
+	if m.GetMaxRetransmitSlotFunc == nil {
+		var zero Slot
+		return zero, fmt.Errorf("GetMaxRetransmitSlot: %w", ErrNotMocked)
+	}
+	return m.GetMaxRetransmitSlotFunc(ctx)
+}
+
+func (m *MockRPC) GetMaxShredInsertSlot(ctx context.Context) (Slot, error) {
+	m.record("GetMaxShredInsertSlot")
+	if m.GetMaxShredInsertSlotFunc == nil {
+		var zero Slot
+		return zero, fmt.Errorf("GetMaxShredInsertSlot: %w", ErrNotMocked)
+	}
+	return m.GetMaxShredInsertSlotFunc(ctx)
+}
+
+func (m *MockRPC) GetMinimumBalanceForRentExemption(ctx context.Context, dataLength int64, config *CommitmentConfig) (int64, error) {
+	m.record("GetMinimumBalanceForRentExemption", dataLength, config)
+	if m.GetMinimumBalanceForRentExemptionFunc == nil {
+		var zero int64
+		return zero, fmt.Errorf("GetMinimumBalanceForRentExemption: %w", ErrNotMocked)
+	}
+	return m.GetMinimumBalanceForRentExemptionFunc(ctx, dataLength, config)
+}
+
+func (m *MockRPC) GetMultipleAccounts(ctx context.Context, pubkeys []Pubkey, config *GetAccountInfoConfig) (MultipleAccountsResponse, error) {
+	m.record("GetMultipleAccounts", pubkeys, config)
+	if m.GetMultipleAccountsFunc == nil {
+		var zero MultipleAccountsResponse
+		return zero, fmt.Errorf("GetMultipleAccounts: %w", ErrNotMocked)
+	}
+	return m.GetMultipleAccountsFunc(ctx, pubkeys, config)
+}
+
+func (m *MockRPC) GetProgramAccounts(ctx context.Context, programId Pubkey, config *GetProgramAccountsConfig) ([]ProgramAccount, error) {
+	m.record("GetProgramAccounts", programId, config)
+	if m.GetProgramAccountsFunc == nil {
+		var zero []ProgramAccount
+		return zero, fmt.Errorf("GetProgramAccounts: %w", ErrNotMocked)
+	}
+	return m.GetProgramAccountsFunc(ctx, programId, config)
+}
+
+func (m *MockRPC) GetRecentPerformanceSamples(ctx context.Context, limit *int64) ([]PerformanceSample, error) {
+	m.record("GetRecentPerformanceSamples", limit)
+	if m.GetRecentPerformanceSamplesFunc == nil {
+		var zero []PerformanceSample
+		return zero, fmt.Errorf("GetRecentPerformanceSamples: %w", ErrNotMocked)
+	}
+	return m.GetRecentPerformanceSamplesFunc(ctx, limit)
+}
+
+func (m *MockRPC) GetRecentPrioritizationFees(ctx context.Context, addresses *[]Pubkey) ([]PrioritizationFee, error) {
+	m.record("GetRecentPrioritizationFees", addresses)
+	if m.GetRecentPrioritizationFeesFunc == nil {
+		var zero []PrioritizationFee
+		return zero, fmt.Errorf("GetRecentPrioritizationFees: %w", ErrNotMocked)
+	}
+	return m.GetRecentPrioritizationFeesFunc(ctx, addresses)
+}
+
+func (m *MockRPC) GetSignatureStatuses(ctx context.Context, signatures []Signature, config *GetSignatureStatusesConfig) (SignatureStatusesResponse, error) {
+	m.record("GetSignatureStatuses", signatures, config)
+	if m.GetSignatureStatusesFunc == nil {
+		var zero SignatureStatusesResponse
+		return zero, fmt.Errorf("GetSignatureStatuses: %w", ErrNotMocked)
+	}
+	return m.GetSignatureStatusesFunc(ctx, signatures, config)
+}
+
+func (m *MockRPC) GetSignaturesForAddress(ctx context.Context, address Pubkey, config *GetSignaturesForAddressConfig) ([]SignatureInfo, error) {
+	m.record("GetSignaturesForAddress", address, config)
+	if m.GetSignaturesForAddressFunc == nil {
+		var zero []SignatureInfo
+		return zero, fmt.Errorf("GetSignaturesForAddress: %w", ErrNotMocked)
+	}
+	return m.GetSignaturesForAddressFunc(ctx, address, config)
+}
+
+func (m *MockRPC) GetSlot(ctx context.Context, config *CommitmentConfig) (Slot, error) {
+	m.record("GetSlot", config)
+	if m.GetSlotFunc == nil {
+		var zero Slot
+		return zero, fmt.Errorf("GetSlot: %w", ErrNotMocked)
+	}
+	return m.GetSlotFunc(ctx, config)
+}
+
+func (m *MockRPC) GetSlotLeader(ctx context.Context, config *CommitmentConfig) (Pubkey, error) {
+	m.record("GetSlotLeader", config)
+	if m.GetSlotLeaderFunc == nil {
+		var zero Pubkey
+		return zero, fmt.Errorf("GetSlotLeader: %w", ErrNotMocked)
+	}
+	return m.GetSlotLeaderFunc(ctx, config)
+}
+
+func (m *MockRPC) GetSlotLeaders(ctx context.Context, startSlot Slot, limit int64) ([]Pubkey, error) {
+	m.record("GetSlotLeaders", startSlot, limit)
+	if m.GetSlotLeadersFunc == nil {
+		var zero []Pubkey
+		return zero, fmt.Errorf("GetSlotLeaders: %w", ErrNotMocked)
+	}
+	return m.GetSlotLeadersFunc(ctx, startSlot, limit)
+}
+
+func (m *MockRPC) GetStakeMinimumDelegation(ctx context.Context, config *CommitmentConfig) (RpcResponseU64, error) {
+	m.record("GetStakeMinimumDelegation", config)
+	if m.GetStakeMinimumDelegationFunc == nil {
+		var zero RpcResponseU64
+		return zero, fmt.Errorf("GetStakeMinimumDelegation: %w", ErrNotMocked)
+	}
+	return m.GetStakeMinimumDelegationFunc(ctx, config)
+}
+
+func (m *MockRPC) GetSupply(ctx context.Context, config *GetSupplyConfig) (SupplyResponse, error) {
+	m.record("GetSupply", config)
+	if m.GetSupplyFunc == nil {
+		var zero SupplyResponse
+		return zero, fmt.Errorf("GetSupply: %w", ErrNotMocked)
+	}
+	return m.GetSupplyFunc(ctx, config)
+}
+
+func (m *MockRPC) GetTokenAccountBalance(ctx context.Context, pubkey Pubkey, config *CommitmentConfig) (TokenBalanceResponse, error) {
+	m.record("GetTokenAccountBalance", pubkey, config)
+	if m.GetTokenAccountBalanceFunc == nil {
+		var zero TokenBalanceResponse
+		return zero, fmt.Errorf("GetTokenAccountBalance: %w", ErrNotMocked)
+	}
+	return m.GetTokenAccountBalanceFunc(ctx, pubkey, config)
+}
+
+func (m *MockRPC) GetTokenAccountsByDelegate(ctx context.Context, delegate Pubkey, filter TokenAccountsFilter, config *GetTokenAccountsConfig) (TokenAccountsResponse, error) {
+	m.record("GetTokenAccountsByDelegate", delegate, filter, config)
+	if m.GetTokenAccountsByDelegateFunc == nil {
+		var zero TokenAccountsResponse
+		return zero, fmt.Errorf("GetTokenAccountsByDelegate: %w", ErrNotMocked)
+	}
+	return m.GetTokenAccountsByDelegateFunc(ctx, delegate, filter, config)
+}
+
+func (m *MockRPC) GetTokenAccountsByOwner(ctx context.Context, owner Pubkey, filter TokenAccountsFilter, config *GetTokenAccountsConfig) (TokenAccountsResponse, error) {
+	m.record("GetTokenAccountsByOwner", owner, filter, config)
+	if m.GetTokenAccountsByOwnerFunc == nil {
+		var zero TokenAccountsResponse
+		return zero, fmt.Errorf("GetTokenAccountsByOwner: %w", ErrNotMocked)
+	}
+	return m.GetTokenAccountsByOwnerFunc(ctx, owner, filter, config)
+}
+
+func (m *MockRPC) GetTokenLargestAccounts(ctx context.Context, mint Pubkey, config *CommitmentConfig) (TokenLargestAccountsResponse, error) {
+	m.record("GetTokenLargestAccounts", mint, config)
+	if m.GetTokenLargestAccountsFunc == nil {
+		var zero TokenLargestAccountsResponse
+		return zero, fmt.Errorf("GetTokenLargestAccounts: %w", ErrNotMocked)
+	}
+	return m.GetTokenLargestAccountsFunc(ctx, mint, config)
+}
+
+func (m *MockRPC) GetTokenSupply(ctx context.Context, mint Pubkey, config *CommitmentConfig) (TokenBalanceResponse, error) {
+	m.record("GetTokenSupply", mint, config)
+	if m.GetTokenSupplyFunc == nil {
+		var zero TokenBalanceResponse
+		return zero, fmt.Errorf("GetTokenSupply: %w", ErrNotMocked)
+	}
+	return m.GetTokenSupplyFunc(ctx, mint, config)
+}
+
+func (m *MockRPC) GetTransaction(ctx context.Context, signature Signature, config *GetTransactionConfig) (TransactionResponse, error) {
+	m.record("GetTransaction", signature, config)
+	if m.GetTransactionFunc == nil {
+		var zero TransactionResponse
+		return zero, fmt.Errorf("GetTransaction: %w", ErrNotMocked)
+	}
+	return m.GetTransactionFunc(ctx, signature, config)
+}
+
+func (m *MockRPC) GetTransactionCount(ctx context.Context, config *CommitmentConfig) (int64, error) {
+	m.record("GetTransactionCount", config)
+	if m.GetTransactionCountFunc == nil {
+		var zero int64
+		return zero, fmt.Errorf("GetTransactionCount: %w", ErrNotMocked)
+	}
+	return m.GetTransactionCountFunc(ctx, config)
+}
+
+func (m *MockRPC) GetVersion(ctx context.Context) (Version, error) {
+	m.record("GetVersion")
+	if m.GetVersionFunc == nil {
+		var zero Version
+		return zero, fmt.Errorf("GetVersion: %w", ErrNotMocked)
+	}
+	return m.GetVersionFunc(ctx)
+}
+
+func (m *MockRPC) GetVoteAccounts(ctx context.Context, config *GetVoteAccountsConfig) (VoteAccountsResponse, error) {
+	m.record("GetVoteAccounts", config)
+	if m.GetVoteAccountsFunc == nil {
+		var zero VoteAccountsResponse
+		return zero, fmt.Errorf("GetVoteAccounts: %w", ErrNotMocked)
+	}
+	return m.GetVoteAccountsFunc(ctx, config)
+}
+
+func (m *MockRPC) IsBlockhashValid(ctx context.Context, blockhash Hash, config *CommitmentConfig) (RpcResponseBool, error) {
+	m.record("IsBlockhashValid", blockhash, config)
+	if m.IsBlockhashValidFunc == nil {
+		var zero RpcResponseBool
+		return zero, fmt.Errorf("IsBlockhashValid: %w", ErrNotMocked)
+	}
+	return m.IsBlockhashValidFunc(ctx, blockhash, config)
+}
+
+func (m *MockRPC) MinimumLedgerSlot(ctx context.Context) (Slot, error) {
+	m.record("MinimumLedgerSlot")
+	if m.MinimumLedgerSlotFunc == nil {
+		var zero Slot
+		return zero, fmt.Errorf("MinimumLedgerSlot: %w", ErrNotMocked)
+	}
+	return m.MinimumLedgerSlotFunc(ctx)
+}
+
+func (m *MockRPC) RequestAirdrop(ctx context.Context, pubkey Pubkey, lamports int64, config *CommitmentConfig) (Signature, error) {
+	m.record("RequestAirdrop", pubkey, lamports, config)
+	if m.RequestAirdropFunc == nil {
+		var zero Signature
+		return zero, fmt.Errorf("RequestAirdrop: %w", ErrNotMocked)
+	}
+	return m.RequestAirdropFunc(ctx, pubkey, lamports, config)
+}
+
+func (m *MockRPC) SendTransaction(ctx context.Context, transaction string, config *SendTransactionConfig) (Signature, error) {
+	m.record("SendTransaction", transaction, config)
+	if m.SendTransactionFunc == nil {
+		var zero Signature
+		return zero, fmt.Errorf("SendTransaction: %w", ErrNotMocked)
+	}
+	return m.SendTransactionFunc(ctx, transaction, config)
+}
+
+func (m *MockRPC) SimulateTransaction(ctx context.Context, transaction string, config *SimulateTransactionConfig) (SimulateTransactionResponse, error) {
+	m.record("SimulateTransaction", transaction, config)
+	if m.SimulateTransactionFunc == nil {
+		var zero SimulateTransactionResponse
+		return zero, fmt.Errorf("SimulateTransaction: %w", ErrNotMocked)
+	}
+	return m.SimulateTransactionFunc(ctx, transaction, config)
+}
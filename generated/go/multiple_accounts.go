@@ -0,0 +1,119 @@
+package solana
+
+import (
+	"context"
+	"fmt"
+	"sync"
+)
+
+// MaxMultipleAccounts is the server-side limit on pubkeys per getMultipleAccounts request.
+const MaxMultipleAccounts = 100
+
+// DefaultMultipleAccountsConcurrency is the number of chunks fetched in parallel
+// by GetMultipleAccountsChunked when no concurrency is given.
+const DefaultMultipleAccountsConcurrency = 4
+
+// GetMultipleAccountsChunked fetches any number of accounts by splitting pubkeys
+// into chunks of MaxMultipleAccounts and requesting them with at most
+// concurrency chunks in flight. Values are returned in input order.
+//
+// The first chunk is fetched on its own and its context slot becomes the floor
+// for the rest: every other chunk is requested with MinContextSlot set to it and
+// rejected if answered below it. The returned Context is that of the first
+// chunk, so every value is at least as fresh as Context.Slot.
+func (c *Client) GetMultipleAccountsChunked(ctx context.Context, pubkeys []Pubkey, config *GetAccountInfoConfig, concurrency int) (MultipleAccountsResponse, error) {
+	if concurrency <= 0 {
+		concurrency = DefaultMultipleAccountsConcurrency
+	}
+
+	var chunks [][]Pubkey
+	for start := 0; start < len(pubkeys); start += MaxMultipleAccounts {
+		end := start + MaxMultipleAccounts
+		if end > len(pubkeys) {
+			end = len(pubkeys)
+		}
+		chunks = append(chunks, pubkeys[start:end])
+	}
+	if len(chunks) <= 1 {
+		return c.GetMultipleAccounts(ctx, pubkeys, config)
+	}
+
+	first, err := c.GetMultipleAccounts(ctx, chunks[0], config)
+	if err != nil {
+		return MultipleAccountsResponse{}, fmt.Errorf("chunk 0: %w", err)
+	}
+	if len(first.Value) != len(chunks[0]) {
+		return MultipleAccountsResponse{}, fmt.Errorf("chunk 0: got %d accounts, want %d", len(first.Value), len(chunks[0]))
+	}
+
+	floor := first.Context.Slot
+	chunkConfig := GetAccountInfoConfig{}
+	if config != nil {
+		chunkConfig = *config
+	}
+	if chunkConfig.MinContextSlot < floor {
+		chunkConfig.MinContextSlot = floor
+	}
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	results := make([]MultipleAccountsResponse, len(chunks))
+	results[0] = first
+
+	var (
+		wg       sync.WaitGroup
+		errOnce  sync.Once
+		firstErr error
+		sem      = make(chan struct{}, concurrency)
+	)
+	for i := 1; i < len(chunks); i++ {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			cfg := chunkConfig
+			resp, err := c.GetMultipleAccounts(ctx, chunks[i], &cfg)
+			if err == nil && len(resp.Value) != len(chunks[i]) {
+				err = fmt.Errorf("got %d accounts, want %d", len(resp.Value), len(chunks[i]))
+			}
+			if err == nil && resp.Context.Slot < floor {
+				err = fmt.Errorf("answered at slot %d, below snapshot slot %d", resp.Context.Slot, floor)
+			}
+			if err != nil {
+				errOnce.Do(func() {
+					firstErr = fmt.Errorf("chunk %d: %w", i, err)
+					cancel()
+				})
+				return
+			}
+			results[i] = resp
+		}(i)
+	}
+	wg.Wait()
+
+	if firstErr != nil {
+		return MultipleAccountsResponse{}, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return MultipleAccountsResponse{}, err
+	}
+
+	out := MultipleAccountsResponse{
+		Context: first.Context,
+		Value:   make([]AccountInfo, 0, len(pubkeys)),
+	}
+	for _, r := range results {
+		out.Value = append(out.Value, r.Value...)
+	}
+	return out, nil
+}
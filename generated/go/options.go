@@ -0,0 +1,26 @@
+package solana
+
+import "net/http"
+
+// ClientOption configures a Client at construction time.
+type ClientOption func(*Client)
+
+// WithHTTPClient sets the HTTP client used to send requests, for custom
+// timeouts, proxies or round trippers. The default is http.DefaultClient.
+func WithHTTPClient(httpClient *http.Client) ClientOption {
+	return func(c *Client) {
+		c.httpClient = httpClient
+	}
+}
+
+// WithDefaultCommitment sets the commitment filled into every outgoing config
+// that leaves its commitment unset, so a whole service can read at, say,
+// CommitmentConfirmed without passing a config to each call. A commitment set
+// on the config itself, or with WithCommitment on the call's context, wins.
+// Methods that reject CommitmentProcessed, such as GetBlock and
+// GetTransaction, get CommitmentConfirmed in its place.
+func WithDefaultCommitment(commitment Commitment) ClientOption {
+	return func(c *Client) {
+		c.defaultCommitment = commitment
+	}
+}
@@ -0,0 +1,173 @@
+// Package otelsolana instruments a solana.Client with OpenTelemetry tracing
+// and metrics. It lives in its own module so the client itself carries no
+// OpenTelemetry dependency.
+//
+//	interceptor, err := otelsolana.NewInterceptor()
+//	if err != nil {
+//		return err
+//	}
+//	client := solana.NewClient(endpoint, solana.WithInterceptors(interceptor))
+package otelsolana
+
+import (
+	"context"
+	"errors"
+	"net/url"
+	"strconv"
+	"time"
+
+	solana "github.com/solana-rpc/client"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/metric"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/solana-rpc/client/otelsolana"
+
+// Attribute keys set on spans and metrics.
+const (
+	AttrRPCSystem    = attribute.Key("rpc.system")
+	AttrRPCMethod    = attribute.Key("rpc.method")
+	AttrServerHost   = attribute.Key("server.address")
+	AttrCommitment   = attribute.Key("solana.commitment")
+	AttrContextSlot  = attribute.Key("solana.context_slot")
+	AttrRPCErrorCode = attribute.Key("rpc.jsonrpc.error_code")
+	AttrHTTPStatus   = attribute.Key("http.response.status_code")
+	AttrErrorType    = attribute.Key("error.type")
+)
+
+type config struct {
+	tracerProvider trace.TracerProvider
+	meterProvider  metric.MeterProvider
+}
+
+// Option configures NewInterceptor.
+type Option func(*config)
+
+// WithTracerProvider sets the tracer provider. The default is the global one.
+func WithTracerProvider(tp trace.TracerProvider) Option {
+	return func(c *config) {
+		c.tracerProvider = tp
+	}
+}
+
+// WithMeterProvider sets the meter provider. The default is the global one.
+func WithMeterProvider(mp metric.MeterProvider) Option {
+	return func(c *config) {
+		c.meterProvider = mp
+	}
+}
+
+// NewInterceptor returns an interceptor that wraps each call in a client span
+// named after the RPC method and records its latency, response size and
+// failures per method.
+//
+// Spans carry the endpoint host, the requested commitment, the response
+// context slot, the HTTP status and, for failed calls, the JSON-RPC error code.
+// The endpoint path and query are never recorded, since providers often embed
+// API keys there.
+func NewInterceptor(opts ...Option) (solana.Interceptor, error) {
+	cfg := config{
+		tracerProvider: otel.GetTracerProvider(),
+		meterProvider:  otel.GetMeterProvider(),
+	}
+	for _, opt := range opts {
+		opt(&cfg)
+	}
+
+	tracer := cfg.tracerProvider.Tracer(instrumentationName)
+	meter := cfg.meterProvider.Meter(instrumentationName)
+
+	duration, err := meter.Float64Histogram("solana.rpc.client.duration",
+		metric.WithDescription("Duration of Solana RPC calls."),
+		metric.WithUnit("s"))
+	if err != nil {
+		return nil, err
+	}
+	responseSize, err := meter.Int64Histogram("solana.rpc.client.response.size",
+		metric.WithDescription("Size of Solana RPC response bodies read from the network."),
+		metric.WithUnit("By"))
+	if err != nil {
+		return nil, err
+	}
+	failures, err := meter.Int64Counter("solana.rpc.client.errors",
+		metric.WithDescription("Solana RPC calls that failed, by method and error."),
+		metric.WithUnit("{call}"))
+	if err != nil {
+		return nil, err
+	}
+
+	return func(ctx context.Context, req *solana.Request, next solana.Invoker) (*solana.RawResponse, error) {
+		common := []attribute.KeyValue{
+			AttrRPCSystem.String("jsonrpc"),
+			AttrRPCMethod.String(req.Method),
+		}
+		if u, err := url.Parse(req.Endpoint); err == nil {
+			common = append(common, AttrServerHost.String(u.Hostname()))
+		}
+
+		// Copy common so appends for the span and the metrics do not share a
+		// backing array.
+		spanAttrs := append([]attribute.KeyValue(nil), common...)
+		if commitment := req.Commitment(); commitment != "" {
+			spanAttrs = append(spanAttrs, AttrCommitment.String(string(commitment)))
+		}
+		ctx, span := tracer.Start(ctx, req.Method,
+			trace.WithSpanKind(trace.SpanKindClient),
+			trace.WithAttributes(spanAttrs...))
+		defer span.End()
+
+		start := time.Now()
+		resp, err := next(ctx, req)
+		elapsed := time.Since(start).Seconds()
+
+		metricAttrs := append([]attribute.KeyValue(nil), common...)
+		switch {
+		case err != nil:
+			errType := "transport"
+			var statusErr *solana.HTTPStatusError
+			switch {
+			case errors.As(err, &statusErr):
+				// As for HTTP clients, a failed status is its own error type.
+				errType = strconv.Itoa(statusErr.StatusCode)
+				span.SetAttributes(AttrHTTPStatus.Int(statusErr.StatusCode))
+				metricAttrs = append(metricAttrs, AttrHTTPStatus.Int(statusErr.StatusCode))
+			case errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded):
+				errType = "canceled"
+			case errors.Is(err, solana.ErrResponseTooLarge):
+				errType = "response_too_large"
+			case errors.Is(err, solana.ErrBodyReadTimeout):
+				errType = "body_read_timeout"
+			case errors.Is(err, solana.ErrResponseMismatch):
+				errType = "response_mismatch"
+			}
+			span.RecordError(err)
+			span.SetStatus(codes.Error, err.Error())
+			metricAttrs = append(metricAttrs, AttrErrorType.String(errType))
+			failures.Add(ctx, 1, metric.WithAttributes(metricAttrs...))
+		case resp.Error != nil:
+			code := AttrRPCErrorCode.Int(resp.Error.Code)
+			span.SetAttributes(code)
+			span.SetStatus(codes.Error, resp.Error.Message)
+			metricAttrs = append(metricAttrs, AttrErrorType.String("rpc"), code)
+			failures.Add(ctx, 1, metric.WithAttributes(metricAttrs...))
+		}
+
+		if resp != nil {
+			if resp.StatusCode != 0 {
+				span.SetAttributes(AttrHTTPStatus.Int(resp.StatusCode))
+			}
+			if slot, ok := resp.ContextSlot(); ok {
+				span.SetAttributes(AttrContextSlot.Int64(slot))
+			}
+			if resp.BodySize > 0 {
+				responseSize.Record(ctx, resp.BodySize, metric.WithAttributes(common...))
+			}
+		}
+		duration.Record(ctx, elapsed, metric.WithAttributes(metricAttrs...))
+
+		return resp, err
+	}, nil
+}
@@ -0,0 +1,174 @@
+package otelsolana_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"testing"
+
+	solana "github.com/solana-rpc/client"
+	"github.com/solana-rpc/client/otelsolana"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	sdkmetric "go.opentelemetry.io/otel/sdk/metric"
+	"go.opentelemetry.io/otel/sdk/metric/metricdata"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestInterceptor(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	interceptor, err := otelsolana.NewInterceptor(
+		otelsolana.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		otelsolana.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	if err != nil {
+		t.Fatalf("NewInterceptor failed: %v", err)
+	}
+
+	ctx := context.Background()
+	call := func(method string, resp *solana.RawResponse) {
+		req := &solana.Request{
+			Endpoint: "https://rpc.example.com/secret-key?token=x",
+			Method:   method,
+			Params:   []interface{}{solana.CommitmentConfig{Commitment: solana.CommitmentConfirmed}},
+		}
+		interceptor(ctx, req, func(context.Context, *solana.Request) (*solana.RawResponse, error) {
+			return resp, nil
+		})
+	}
+	call("getBalance", &solana.RawResponse{
+		Result:     json.RawMessage(`{"context":{"slot":42},"value":1}`),
+		StatusCode: 200,
+		BodySize:   40,
+	})
+	call("getSlot", &solana.RawResponse{
+		Error:      &solana.RPCError{Code: -32002, Message: "node is behind"},
+		StatusCode: 200,
+		BodySize:   60,
+	})
+
+	ended := spans.Ended()
+	if len(ended) != 2 {
+		t.Fatalf("expected 2 spans, got %d", len(ended))
+	}
+	ok, failed := ended[0], ended[1]
+	if ok.Name() != "getBalance" || ok.Status().Code == codes.Error {
+		t.Fatalf("unexpected span %s with status %v", ok.Name(), ok.Status())
+	}
+	wantAttrs(t, "span", ok.Attributes(), map[attribute.Key]string{
+		otelsolana.AttrRPCMethod:   "getBalance",
+		otelsolana.AttrServerHost:  "rpc.example.com",
+		otelsolana.AttrCommitment:  "confirmed",
+		otelsolana.AttrContextSlot: "42",
+		otelsolana.AttrHTTPStatus:  "200",
+	})
+	if failed.Status().Code != codes.Error || failed.Status().Description != "node is behind" {
+		t.Fatalf("unexpected failed span status %v", failed.Status())
+	}
+	wantAttrs(t, "failed span", failed.Attributes(), map[attribute.Key]string{
+		otelsolana.AttrRPCErrorCode: "-32002",
+	})
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	metrics := map[string]metricdata.Aggregation{}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			metrics[m.Name] = m.Data
+		}
+	}
+
+	durations := metrics["solana.rpc.client.duration"].(metricdata.Histogram[float64]).DataPoints
+	if len(durations) != 2 {
+		t.Fatalf("expected 2 duration series, got %d", len(durations))
+	}
+	for _, dp := range durations {
+		if _, ok := dp.Attributes.Value(otelsolana.AttrCommitment); ok {
+			t.Errorf("commitment leaked into metric attributes %v", dp.Attributes.ToSlice())
+		}
+	}
+
+	sizes := metrics["solana.rpc.client.response.size"].(metricdata.Histogram[int64]).DataPoints
+	var total int64
+	for _, dp := range sizes {
+		total += dp.Sum
+	}
+	if total != 100 {
+		t.Errorf("expected 100 response bytes recorded, got %d", total)
+	}
+
+	failures := metrics["solana.rpc.client.errors"].(metricdata.Sum[int64]).DataPoints
+	if len(failures) != 1 || failures[0].Value != 1 {
+		t.Fatalf("unexpected failure counts %+v", failures)
+	}
+	wantAttrs(t, "failure", failures[0].Attributes.ToSlice(), map[attribute.Key]string{
+		otelsolana.AttrRPCMethod:    "getSlot",
+		otelsolana.AttrErrorType:    "rpc",
+		otelsolana.AttrRPCErrorCode: "-32002",
+	})
+}
+
+func TestInterceptorHTTPStatus(t *testing.T) {
+	spans := tracetest.NewSpanRecorder()
+	reader := sdkmetric.NewManualReader()
+	interceptor, err := otelsolana.NewInterceptor(
+		otelsolana.WithTracerProvider(sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))),
+		otelsolana.WithMeterProvider(sdkmetric.NewMeterProvider(sdkmetric.WithReader(reader))),
+	)
+	if err != nil {
+		t.Fatalf("NewInterceptor failed: %v", err)
+	}
+
+	ctx := context.Background()
+	req := &solana.Request{Endpoint: "https://rpc.example.com", Method: "getSlot"}
+	interceptor(ctx, req, func(context.Context, *solana.Request) (*solana.RawResponse, error) {
+		return nil, fmt.Errorf("wrapped: %w", &solana.HTTPStatusError{Method: "getSlot", StatusCode: 503})
+	})
+
+	ended := spans.Ended()
+	if len(ended) != 1 || ended[0].Status().Code != codes.Error {
+		t.Fatalf("expected 1 failed span, got %d", len(ended))
+	}
+	wantAttrs(t, "span", ended[0].Attributes(), map[attribute.Key]string{
+		otelsolana.AttrHTTPStatus: "503",
+	})
+
+	var rm metricdata.ResourceMetrics
+	if err := reader.Collect(ctx, &rm); err != nil {
+		t.Fatalf("Collect failed: %v", err)
+	}
+	for _, sm := range rm.ScopeMetrics {
+		for _, m := range sm.Metrics {
+			if m.Name != "solana.rpc.client.errors" {
+				continue
+			}
+			failures := m.Data.(metricdata.Sum[int64]).DataPoints
+			if len(failures) != 1 {
+				t.Fatalf("unexpected failure counts %+v", failures)
+			}
+			wantAttrs(t, "failure", failures[0].Attributes.ToSlice(), map[attribute.Key]string{
+				otelsolana.AttrErrorType:  "503",
+				otelsolana.AttrHTTPStatus: "503",
+			})
+			return
+		}
+	}
+	t.Fatal("no failures recorded")
+}
+
+func wantAttrs(t *testing.T, what string, attrs []attribute.KeyValue, want map[attribute.Key]string) {
+	t.Helper()
+	got := map[attribute.Key]string{}
+	for _, kv := range attrs {
+		got[kv.Key] = kv.Value.Emit()
+	}
+	for k, v := range want {
+		if got[k] != v {
+			t.Errorf("%s attribute %s: got %q, want %q", what, k, got[k], v)
+		}
+	}
+}
@@ -0,0 +1,138 @@
+package solana
+
+import (
+	"context"
+	"fmt"
+	"sort"
+)
+
+// MaxPrioritizationFeeAccounts is the most accounts getRecentPrioritizationFees
+// accepts.
+const MaxPrioritizationFeeAccounts = 128
+
+// PriorityLevel selects one of the recommendations in a PriorityFeeEstimate.
+type PriorityLevel int
+
+const (
+	PriorityMin PriorityLevel = iota
+	PriorityLow
+	PriorityMedium
+	PriorityHigh
+	PriorityP95
+)
+
+// PriorityFeeEstimate holds recommended compute unit prices, in micro-lamports
+// per compute unit, at increasing chances of prompt inclusion. Low, Medium and
+// High are the 25th, 50th and 75th percentiles of recent fees.
+type PriorityFeeEstimate struct {
+	Min    int64
+	Low    int64
+	Medium int64
+	High   int64
+	P95    int64
+
+	// Samples is the number of slots the estimate is based on.
+	Samples int
+}
+
+// Level returns the recommendation for level.
+func (e PriorityFeeEstimate) Level(level PriorityLevel) int64 {
+	switch level {
+	case PriorityMin:
+		return e.Min
+	case PriorityLow:
+		return e.Low
+	case PriorityHigh:
+		return e.High
+	case PriorityP95:
+		return e.P95
+	default:
+		return e.Medium
+	}
+}
+
+// SetComputeUnitPrice returns a compute budget instruction paying the fee
+// recommended for level.
+func (e PriorityFeeEstimate) SetComputeUnitPrice(level PriorityLevel) Instruction {
+	return SetComputeUnitPrice(uint64(e.Level(level)))
+}
+
+// PriorityFeeEstimator recommends priority fees from the fees recently paid
+// by transactions that wrote to the same accounts.
+type PriorityFeeEstimator struct {
+	client RPC
+
+	// Window limits the estimate to the most recent Window slots, smoothing
+	// out older spikes the node still reports. 0 uses every slot returned.
+	Window int
+
+	// Cap bounds every recommendation, in micro-lamports per compute unit.
+	// 0 leaves them uncapped.
+	Cap int64
+}
+
+// NewPriorityFeeEstimator returns an estimator over c, typically a Client or
+// Session, using every recent slot and no cap.
+func NewPriorityFeeEstimator(c RPC) *PriorityFeeEstimator {
+	return &PriorityFeeEstimator{client: c}
+}
+
+// Estimate fetches recent prioritization fees for a transaction writing to
+// writableAccounts and returns percentile-based recommendations. With no
+// accounts the estimate reflects fees paid across whole blocks.
+func (e *PriorityFeeEstimator) Estimate(ctx context.Context, writableAccounts []Pubkey) (PriorityFeeEstimate, error) {
+	if len(writableAccounts) > MaxPrioritizationFeeAccounts {
+		return PriorityFeeEstimate{}, fmt.Errorf("%d accounts exceeds the maximum of %d", len(writableAccounts), MaxPrioritizationFeeAccounts)
+	}
+
+	var addresses *[]Pubkey
+	if len(writableAccounts) > 0 {
+		addresses = &writableAccounts
+	}
+	fees, err := e.client.GetRecentPrioritizationFees(ctx, addresses)
+	if err != nil {
+		return PriorityFeeEstimate{}, err
+	}
+	return e.estimate(fees), nil
+}
+
+func (e *PriorityFeeEstimator) estimate(fees []PrioritizationFee) PriorityFeeEstimate {
+	sort.Slice(fees, func(i, j int) bool { return fees[i].Slot > fees[j].Slot })
+	if e.Window > 0 && len(fees) > e.Window {
+		fees = fees[:e.Window]
+	}
+	if len(fees) == 0 {
+		return PriorityFeeEstimate{}
+	}
+
+	values := make([]int64, len(fees))
+	for i, f := range fees {
+		values[i] = f.PrioritizationFee
+	}
+	sort.Slice(values, func(i, j int) bool { return values[i] < values[j] })
+
+	return PriorityFeeEstimate{
+		Min:     e.capped(values[0]),
+		Low:     e.capped(percentile(values, 25)),
+		Medium:  e.capped(percentile(values, 50)),
+		High:    e.capped(percentile(values, 75)),
+		P95:     e.capped(percentile(values, 95)),
+		Samples: len(values),
+	}
+}
+
+func (e *PriorityFeeEstimator) capped(v int64) int64 {
+	if e.Cap > 0 && v > e.Cap {
+		return e.Cap
+	}
+	return v
+}
+
+// percentile returns the nearest-rank p-th percentile of sorted.
+func percentile(sorted []int64, p int) int64 {
+	rank := (p*len(sorted) + 99) / 100
+	if rank < 1 {
+		rank = 1
+	}
+	return sorted[rank-1]
+}
@@ -0,0 +1,222 @@
+package solana
+
+import (
+	"encoding/base64"
+	"strconv"
+	"strings"
+)
+
+// logTruncated is the line the runtime writes in place of logs past its size
+// limit.
+const logTruncated = "Log truncated"
+
+// ProgramLogs is a transaction's program log rebuilt into its tree of
+// program invocations.
+type ProgramLogs struct {
+	// Invocations are the top-level instructions, in execution order.
+	Invocations []*ProgramInvocation
+
+	// Truncated is set when the runtime cut the log short, so later
+	// invocations and the outcome of unfinished ones are missing.
+	Truncated bool
+}
+
+// ProgramInvocation is one program invocation and the log lines it wrote.
+type ProgramInvocation struct {
+	ProgramID Pubkey
+
+	// Depth is 1 for top-level instructions and one more for each level of
+	// cross-program invocation.
+	Depth int
+
+	// Logs holds the messages written with "Program log:", and any lines
+	// the parser does not recognize, in order.
+	Logs []string
+
+	// Data holds the base64 blobs emitted with "Program data:", such as
+	// Anchor events, one entry per line with the line's fields joined.
+	Data [][]byte
+
+	// ReturnData is what the program passed to set_return_data, if anything.
+	ReturnData []byte
+
+	// ComputeUnitsConsumed is the compute units this invocation used,
+	// including its own inner invocations, out of ComputeUnitsLimit
+	// available to it.
+	ComputeUnitsConsumed int64
+	ComputeUnitsLimit    int64
+
+	// Succeeded and Failed say how the invocation ended; both are false
+	// when the log was truncated before it did. Err is the failure reason.
+	Succeeded bool
+	Failed    bool
+	Err       string
+
+	// Invocations are the programs this one invoked, in order.
+	Invocations []*ProgramInvocation
+}
+
+// ParseProgramLogs rebuilds the invocation tree from the log messages of a
+// transaction, as found in TransactionMeta.LogMessages or simulation logs.
+// Malformed lines never fail the parse; they are kept in the Logs of the
+// invocation they appear in.
+func ParseProgramLogs(logs []string) ProgramLogs {
+	var out ProgramLogs
+	var stack []*ProgramInvocation
+
+	current := func() *ProgramInvocation {
+		if len(stack) == 0 {
+			return nil
+		}
+		return stack[len(stack)-1]
+	}
+	addLog := func(line string) {
+		if inv := current(); inv != nil {
+			inv.Logs = append(inv.Logs, line)
+		}
+	}
+
+	for _, line := range logs {
+		if line == logTruncated {
+			out.Truncated = true
+			continue
+		}
+
+		rest, ok := strings.CutPrefix(line, "Program ")
+		if !ok {
+			addLog(line)
+			continue
+		}
+
+		switch {
+		case strings.HasPrefix(rest, "log: "):
+			addLog(strings.TrimPrefix(rest, "log: "))
+			continue
+		case strings.HasPrefix(rest, "data: "):
+			if data, ok := decodeLogData(strings.Fields(strings.TrimPrefix(rest, "data: "))); ok {
+				if inv := current(); inv != nil {
+					inv.Data = append(inv.Data, data)
+				}
+			} else {
+				addLog(line)
+			}
+			continue
+		case strings.HasPrefix(rest, "return: "):
+			// The program ID comes first, then the data.
+			fields := strings.Fields(strings.TrimPrefix(rest, "return: "))
+			inv := current()
+			if len(fields) == 0 || inv == nil {
+				addLog(line)
+				continue
+			}
+			if data, ok := decodeLogData(fields[1:]); ok {
+				inv.ReturnData = data
+			} else {
+				addLog(line)
+			}
+			continue
+		}
+
+		programID, event, ok := strings.Cut(rest, " ")
+		if !ok {
+			addLog(line)
+			continue
+		}
+		switch {
+		case strings.HasPrefix(event, "invoke ["):
+			depth, err := strconv.Atoi(strings.TrimSuffix(strings.TrimPrefix(event, "invoke ["), "]"))
+			if err != nil || depth < 1 {
+				addLog(line)
+				continue
+			}
+			// Invocations left open by a malformed log end where a
+			// shallower one starts.
+			if len(stack) >= depth {
+				stack = stack[:depth-1]
+			}
+			inv := &ProgramInvocation{ProgramID: programID, Depth: depth}
+			if parent := current(); parent != nil {
+				parent.Invocations = append(parent.Invocations, inv)
+			} else {
+				out.Invocations = append(out.Invocations, inv)
+			}
+			stack = append(stack, inv)
+
+		case strings.HasPrefix(event, "consumed "):
+			// "consumed N of M compute units"
+			fields := strings.Fields(event)
+			inv := current()
+			if len(fields) < 4 || fields[2] != "of" || inv == nil || inv.ProgramID != programID {
+				addLog(line)
+				continue
+			}
+			consumed, err1 := strconv.ParseInt(fields[1], 10, 64)
+			limit, err2 := strconv.ParseInt(fields[3], 10, 64)
+			if err1 != nil || err2 != nil {
+				addLog(line)
+				continue
+			}
+			inv.ComputeUnitsConsumed, inv.ComputeUnitsLimit = consumed, limit
+
+		case event == "success":
+			if inv := current(); inv != nil && inv.ProgramID == programID {
+				inv.Succeeded = true
+				stack = stack[:len(stack)-1]
+			} else {
+				addLog(line)
+			}
+
+		case strings.HasPrefix(event, "failed"):
+			if inv := current(); inv != nil && inv.ProgramID == programID {
+				inv.Failed = true
+				inv.Err = strings.TrimPrefix(strings.TrimPrefix(event, "failed"), ": ")
+				stack = stack[:len(stack)-1]
+			} else {
+				addLog(line)
+			}
+
+		default:
+			addLog(line)
+		}
+	}
+	return out
+}
+
+// FailedInvocation returns the deepest invocation that failed, which is the
+// one whose error the failures of its callers propagate, or nil if none did.
+func (l ProgramLogs) FailedInvocation() *ProgramInvocation {
+	var deepest *ProgramInvocation
+	l.Walk(func(inv *ProgramInvocation) {
+		if inv.Failed && (deepest == nil || inv.Depth > deepest.Depth) {
+			deepest = inv
+		}
+	})
+	return deepest
+}
+
+// Walk calls fn for every invocation in execution order, callers before the
+// programs they invoke.
+func (l ProgramLogs) Walk(fn func(*ProgramInvocation)) {
+	var walk func([]*ProgramInvocation)
+	walk = func(invs []*ProgramInvocation) {
+		for _, inv := range invs {
+			fn(inv)
+			walk(inv.Invocations)
+		}
+	}
+	walk(l.Invocations)
+}
+
+// decodeLogData decodes the space-separated base64 fields of a "Program
+// data:" or "Program return:" line and concatenates them.
+func decodeLogData(fields []string) ([]byte, bool) {
+	var out []byte
+	for _, f := range fields {
+		b, err := base64.StdEncoding.DecodeString(f)
+		if err != nil {
+			return nil, false
+		}
+		out = append(out, b...)
+	}
+	return out, true
+}
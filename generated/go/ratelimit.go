@@ -0,0 +1,170 @@
+package solana
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// WithRateLimit caps the client at rps requests per second on average, with
+// bursts of up to burst requests. Calls over the limit wait for capacity
+// rather than fail, and return the context's error if it ends first. A call's
+// cost is its method weight; see WithMethodWeight. rps <= 0 removes the limit.
+func WithRateLimit(rps float64, burst int) ClientOption {
+	return func(c *Client) {
+		if rps <= 0 {
+			c.rateLimiter().bucket = nil
+			return
+		}
+		if burst < 1 {
+			burst = 1
+		}
+		c.rateLimiter().bucket = &tokenBucket{
+			rps:    rps,
+			burst:  float64(burst),
+			tokens: float64(burst),
+			last:   time.Now(),
+		}
+	}
+}
+
+// WithMaxInFlight caps how many requests the client has outstanding at once.
+// Further calls wait for one to finish, or for their context to end. n <= 0
+// removes the limit.
+func WithMaxInFlight(n int) ClientOption {
+	return func(c *Client) {
+		if n <= 0 {
+			c.rateLimiter().inFlight = nil
+			return
+		}
+		c.rateLimiter().inFlight = make(chan struct{}, n)
+	}
+}
+
+// WithMethodWeight sets how many requests a call to the JSON-RPC method
+// counts as against WithRateLimit, for providers that bill expensive methods
+// as several requests. For example
+//
+//	solana.WithMethodWeight("getProgramAccounts", 10)
+//
+// Methods without a weight count as 1, as do weights below 1. Weights above
+// the burst size count as the burst size, so such calls still get through.
+func WithMethodWeight(method string, weight int) ClientOption {
+	return func(c *Client) {
+		if weight < 1 {
+			weight = 1
+		}
+		c.rateLimiter().weights[method] = weight
+	}
+}
+
+// limiter holds the client's rate and concurrency limits. It is shared by
+// pointer, so sessions draw on their client's capacity.
+type limiter struct {
+	weights  map[string]int
+	bucket   *tokenBucket
+	inFlight chan struct{}
+}
+
+func (c *Client) rateLimiter() *limiter {
+	if c.limiter == nil {
+		c.limiter = &limiter{weights: make(map[string]int)}
+	}
+	return c.limiter
+}
+
+func (l *limiter) weight(method string) int {
+	if w, ok := l.weights[method]; ok {
+		return w
+	}
+	return 1
+}
+
+// acquire waits until method may be sent and returns a func that releases
+// its in-flight slot.
+func (l *limiter) acquire(ctx context.Context, method string) (func(), error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if l.bucket != nil {
+		if err := l.bucket.wait(ctx, l.weight(method)); err != nil {
+			return nil, err
+		}
+	}
+	if l.inFlight == nil {
+		return func() {}, nil
+	}
+	select {
+	case l.inFlight <- struct{}{}:
+		return func() { <-l.inFlight }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+func (l *limiter) wrap(next Invoker) Invoker {
+	return func(ctx context.Context, req *Request) (*RawResponse, error) {
+		release, err := l.acquire(ctx, req.Method)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+		return next(ctx, req)
+	}
+}
+
+// tokenBucket is a token bucket refilled continuously at rps. Callers reserve
+// tokens up front, letting the balance go negative, and then sleep until the
+// refill would have covered them, so waiters are served in arrival order.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rps    float64
+	burst  float64
+	tokens float64
+	last   time.Time
+}
+
+func (b *tokenBucket) wait(ctx context.Context, n int) error {
+	cost := float64(n)
+	if cost > b.burst {
+		cost = b.burst
+	}
+
+	b.mu.Lock()
+	b.refill(time.Now())
+	b.tokens -= cost
+	var delay time.Duration
+	if b.tokens < 0 {
+		delay = time.Duration(-b.tokens / b.rps * float64(time.Second))
+	}
+	b.mu.Unlock()
+
+	if delay <= 0 {
+		return nil
+	}
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-timer.C:
+		return nil
+	case <-ctx.Done():
+		// Hand the reservation back so later callers do not wait for it.
+		b.mu.Lock()
+		b.refill(time.Now())
+		b.tokens += cost
+		if b.tokens > b.burst {
+			b.tokens = b.burst
+		}
+		b.mu.Unlock()
+		return ctx.Err()
+	}
+}
+
+// refill credits the tokens earned since the last call. b.mu must be held.
+func (b *tokenBucket) refill(now time.Time) {
+	b.tokens += now.Sub(b.last).Seconds() * b.rps
+	if b.tokens > b.burst {
+		b.tokens = b.burst
+	}
+	b.last = now
+}
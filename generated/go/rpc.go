@@ -0,0 +1,68 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+)
+
+// RPC is the set of methods implemented by Client. Depend on it instead of
+// *Client to substitute a MockRPC in tests or to wrap a client with extra
+// behaviour.
+type RPC interface {
+	Call(ctx context.Context, method string, params []interface{}, result interface{}) error
+	CallRaw(ctx context.Context, method string, params []interface{}) (json.RawMessage, error)
+	GetAccountInfo(ctx context.Context, pubkey Pubkey, config *GetAccountInfoConfig) (AccountInfoResponse, error)
+	GetBalance(ctx context.Context, pubkey Pubkey, config *CommitmentConfig) (RpcResponseU64, error)
+	GetBlock(ctx context.Context, slot Slot, config *GetBlockConfig) (Block, error)
+	GetBlockCommitment(ctx context.Context, slot Slot) (BlockCommitment, error)
+	GetBlockHeight(ctx context.Context, config *CommitmentConfig) (int64, error)
+	GetBlockProduction(ctx context.Context, config *GetBlockProductionConfig) (BlockProduction, error)
+	GetBlockTime(ctx context.Context, slot Slot) (int64, error)
+	GetBlocks(ctx context.Context, startSlot Slot, endSlot *Slot, config *CommitmentConfig) ([]Slot, error)
+	GetBlocksWithLimit(ctx context.Context, startSlot Slot, limit int64, config *CommitmentConfig) ([]Slot, error)
+	GetClusterNodes(ctx context.Context) ([]ClusterNode, error)
+	GetEpochInfo(ctx context.Context, config *CommitmentConfig) (EpochInfo, error)
+	GetEpochSchedule(ctx context.Context) (EpochSchedule, error)
+	GetFeeForMessage(ctx context.Context, message string, config *CommitmentConfig) (RpcResponseU64, error)
+	GetFirstAvailableBlock(ctx context.Context) (Slot, error)
+	GetGenesisHash(ctx context.Context) (Hash, error)
+	GetHealth(ctx context.Context) (string, error)
+	GetHighestSnapshotSlot(ctx context.Context) (SnapshotSlotInfo, error)
+	GetIdentity(ctx context.Context) (map[string]interface{}, error)
+	GetInflationGovernor(ctx context.Context, config *CommitmentConfig) (InflationGovernor, error)
+	GetInflationRate(ctx context.Context) (InflationRate, error)
+	GetInflationReward(ctx context.Context, addresses []Pubkey, config *GetInflationRewardConfig) ([]InflationReward, error)
+	GetLargestAccounts(ctx context.Context, config *GetLargestAccountsConfig) (RpcResponseLargestAccounts, error)
+	GetLatestBlockhash(ctx context.Context, config *CommitmentConfig) (LatestBlockhashResponse, error)
+	GetLeaderSchedule(ctx context.Context, slot *Slot, config *GetLeaderScheduleConfig) (LeaderSchedule, error)
+	GetMaxRetransmitSlot(ctx context.Context) (Slot, error)
+	GetMaxShredInsertSlot(ctx context.Context) (Slot, error)
+	GetMinimumBalanceForRentExemption(ctx context.Context, dataLength int64, config *CommitmentConfig) (int64, error)
+	GetMultipleAccounts(ctx context.Context, pubkeys []Pubkey, config *GetAccountInfoConfig) (MultipleAccountsResponse, error)
+	GetProgramAccounts(ctx context.Context, programId Pubkey, config *GetProgramAccountsConfig) ([]ProgramAccount, error)
+	GetRecentPerformanceSamples(ctx context.Context, limit *int64) ([]PerformanceSample, error)
+	GetRecentPrioritizationFees(ctx context.Context, addresses *[]Pubkey) ([]PrioritizationFee, error)
+	GetSignatureStatuses(ctx context.Context, signatures []Signature, config *GetSignatureStatusesConfig) (SignatureStatusesResponse, error)
+	GetSignaturesForAddress(ctx context.Context, address Pubkey, config *GetSignaturesForAddressConfig) ([]SignatureInfo, error)
+	GetSlot(ctx context.Context, config *CommitmentConfig) (Slot, error)
+	GetSlotLeader(ctx context.Context, config *CommitmentConfig) (Pubkey, error)
+	GetSlotLeaders(ctx context.Context, startSlot Slot, limit int64) ([]Pubkey, error)
+	GetStakeMinimumDelegation(ctx context.Context, config *CommitmentConfig) (RpcResponseU64, error)
+	GetSupply(ctx context.Context, config *GetSupplyConfig) (SupplyResponse, error)
+	GetTokenAccountBalance(ctx context.Context, pubkey Pubkey, config *CommitmentConfig) (TokenBalanceResponse, error)
+	GetTokenAccountsByDelegate(ctx context.Context, delegate Pubkey, filter TokenAccountsFilter, config *GetTokenAccountsConfig) (TokenAccountsResponse, error)
+	GetTokenAccountsByOwner(ctx context.Context, owner Pubkey, filter TokenAccountsFilter, config *GetTokenAccountsConfig) (TokenAccountsResponse, error)
+	GetTokenLargestAccounts(ctx context.Context, mint Pubkey, config *CommitmentConfig) (TokenLargestAccountsResponse, error)
+	GetTokenSupply(ctx context.Context, mint Pubkey, config *CommitmentConfig) (TokenBalanceResponse, error)
+	GetTransaction(ctx context.Context, signature Signature, config *GetTransactionConfig) (TransactionResponse, error)
+	GetTransactionCount(ctx context.Context, config *CommitmentConfig) (int64, error)
+	GetVersion(ctx context.Context) (Version, error)
+	GetVoteAccounts(ctx context.Context, config *GetVoteAccountsConfig) (VoteAccountsResponse, error)
+	IsBlockhashValid(ctx context.Context, blockhash Hash, config *CommitmentConfig) (RpcResponseBool, error)
+	MinimumLedgerSlot(ctx context.Context) (Slot, error)
+	RequestAirdrop(ctx context.Context, pubkey Pubkey, lamports int64, config *CommitmentConfig) (Signature, error)
+	SendTransaction(ctx context.Context, transaction string, config *SendTransactionConfig) (Signature, error)
+	SimulateTransaction(ctx context.Context, transaction string, config *SimulateTransactionConfig) (SimulateTransactionResponse, error)
+}
+
+var _ RPC = (*Client)(nil)
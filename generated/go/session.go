@@ -0,0 +1,93 @@
+package solana
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync/atomic"
+	"time"
+)
+
+// ErrCodeMinContextSlotNotReached is returned by a node that has not yet
+// reached the minContextSlot a request asked for.
+const ErrCodeMinContextSlotNotReached = -32016
+
+const (
+	defaultSessionMaxRetries   = 5
+	defaultSessionRetryBackoff = 100 * time.Millisecond
+)
+
+// Session gives read-your-writes consistency across load-balanced RPC nodes.
+// It records the highest context slot seen in any response and sends it as
+// MinContextSlot on every later call, so no read observes state older than one
+// already seen. Calls a lagging node rejects with
+// ErrCodeMinContextSlotNotReached are retried with exponential backoff.
+//
+// Session embeds a copy of the client it was created from; all Client methods
+// called on it take part in the session. Sessions are safe for concurrent use.
+type Session struct {
+	*Client
+
+	// MaxRetries is how many times a call rejected for not reaching the
+	// session's slot is retried. RetryBackoff is the delay before the first
+	// retry and doubles on each further one.
+	MaxRetries   int
+	RetryBackoff time.Duration
+
+	slot int64
+}
+
+// NewSession returns a session over c. c itself is not affected.
+func NewSession(c *Client) *Session {
+	s := &Session{
+		MaxRetries:   defaultSessionMaxRetries,
+		RetryBackoff: defaultSessionRetryBackoff,
+	}
+	clone := *c
+	clone.session = s
+	s.Client = &clone
+	return s
+}
+
+// Slot returns the highest context slot the session has observed.
+func (s *Session) Slot() Slot {
+	return atomic.LoadInt64(&s.slot)
+}
+
+// Observe raises the session's slot to slot, for example after a write whose
+// landing slot is known from outside the session.
+func (s *Session) Observe(slot Slot) {
+	for {
+		cur := atomic.LoadInt64(&s.slot)
+		if slot <= cur || atomic.CompareAndSwapInt64(&s.slot, cur, slot) {
+			return
+		}
+	}
+}
+
+func (s *Session) send(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {
+	backoff := s.RetryBackoff
+	for attempt := 0; ; attempt++ {
+		raw, err := s.Client.send(ctx, method, params)
+		if err == nil {
+			if slot, ok := (&RawResponse{Result: raw}).ContextSlot(); ok {
+				s.Observe(slot)
+			}
+			return raw, nil
+		}
+
+		var rpcErr *RPCError
+		if attempt >= s.MaxRetries || !errors.As(err, &rpcErr) || rpcErr.Code != ErrCodeMinContextSlotNotReached {
+			return nil, err
+		}
+
+		timer := time.NewTimer(backoff)
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, ctx.Err()
+		case <-timer.C:
+		}
+		backoff *= 2
+	}
+}
@@ -0,0 +1,279 @@
+package solana
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// TransactionError is why a transaction failed, as reported in simulation
+// results and transaction statuses. The node sends either a bare name such
+// as "AccountNotFound" or an object keyed by the name whose value carries
+// details.
+type TransactionError struct {
+	// Kind is the error's name, for example "InstructionError" or
+	// "InsufficientFundsForRent".
+	Kind string
+
+	// InstructionIndex and Instruction are set when Kind is
+	// "InstructionError".
+	InstructionIndex int64
+	Instruction      *InstructionError
+
+	// Raw is the error as sent by the node.
+	Raw json.RawMessage
+}
+
+// InstructionError is why an instruction failed.
+type InstructionError struct {
+	// Kind is the error's name, for example "InvalidAccountData" or
+	// "Custom".
+	Kind string
+
+	// Custom is the program-defined error code when Kind is "Custom".
+	Custom uint32
+
+	Raw json.RawMessage
+}
+
+func (e *TransactionError) Error() string {
+	if e.Instruction != nil {
+		return fmt.Sprintf("instruction %d failed: %v", e.InstructionIndex, e.Instruction)
+	}
+	return e.Kind
+}
+
+func (e *InstructionError) Error() string {
+	if e.Kind == "Custom" {
+		return fmt.Sprintf("custom program error: %#x", e.Custom)
+	}
+	return e.Kind
+}
+
+func (e TransactionError) MarshalJSON() ([]byte, error) {
+	if e.Raw != nil {
+		return e.Raw, nil
+	}
+	return json.Marshal(e.Kind)
+}
+
+func (e *TransactionError) UnmarshalJSON(data []byte) error {
+	kind, details, err := decodeRustEnum(data)
+	if err != nil {
+		return fmt.Errorf("transaction error: %w", err)
+	}
+	*e = TransactionError{Kind: kind, Raw: append(json.RawMessage(nil), data...)}
+	if kind != "InstructionError" {
+		return nil
+	}
+
+	var pair []json.RawMessage
+	if err := json.Unmarshal(details, &pair); err != nil || len(pair) != 2 {
+		return fmt.Errorf("transaction error: malformed InstructionError %s", details)
+	}
+	if err := json.Unmarshal(pair[0], &e.InstructionIndex); err != nil {
+		return fmt.Errorf("transaction error: instruction index: %w", err)
+	}
+	e.Instruction = new(InstructionError)
+	return e.Instruction.UnmarshalJSON(pair[1])
+}
+
+func (e InstructionError) MarshalJSON() ([]byte, error) {
+	if e.Raw != nil {
+		return e.Raw, nil
+	}
+	return json.Marshal(e.Kind)
+}
+
+func (e *InstructionError) UnmarshalJSON(data []byte) error {
+	kind, details, err := decodeRustEnum(data)
+	if err != nil {
+		return fmt.Errorf("instruction error: %w", err)
+	}
+	*e = InstructionError{Kind: kind, Raw: append(json.RawMessage(nil), data...)}
+	if kind == "Custom" {
+		if err := json.Unmarshal(details, &e.Custom); err != nil {
+			return fmt.Errorf("instruction error: custom code: %w", err)
+		}
+	}
+	return nil
+}
+
+// decodeRustEnum splits a serde-encoded enum into its variant name and, for
+// variants with data, the data.
+func decodeRustEnum(data []byte) (string, json.RawMessage, error) {
+	var name string
+	if err := json.Unmarshal(data, &name); err == nil {
+		return name, nil, nil
+	}
+	var variant map[string]json.RawMessage
+	if err := json.Unmarshal(data, &variant); err != nil {
+		return "", nil, err
+	}
+	if len(variant) != 1 {
+		return "", nil, fmt.Errorf("expected one variant, got %s", data)
+	}
+	for name, details := range variant {
+		return name, details, nil
+	}
+	return "", nil, nil
+}
+
+// AccountData is an account's data as returned by account queries. Binary
+// encodings are decoded into Bytes, except base64+zstd, whose Bytes are left
+// compressed; jsonParsed data is kept in Parsed.
+type AccountData struct {
+	Bytes    []byte
+	Encoding Encoding
+	Parsed   json.RawMessage
+}
+
+func (d AccountData) MarshalJSON() ([]byte, error) {
+	switch d.Encoding {
+	case "":
+		return []byte("null"), nil
+	case EncodingJsonParsed:
+		return d.Parsed, nil
+	case EncodingBase58:
+		return json.Marshal([]string{EncodeBase58(d.Bytes), string(d.Encoding)})
+	default:
+		return json.Marshal([]string{base64.StdEncoding.EncodeToString(d.Bytes), string(d.Encoding)})
+	}
+}
+
+func (d *AccountData) UnmarshalJSON(data []byte) error {
+	*d = AccountData{}
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '{' {
+		d.Encoding = EncodingJsonParsed
+		d.Parsed = append(json.RawMessage(nil), data...)
+		return nil
+	}
+
+	// The deprecated binary encoding sends a bare base58 string.
+	var encoded string
+	if err := json.Unmarshal(data, &encoded); err == nil {
+		d.Encoding = EncodingBase58
+	} else {
+		var pair []string
+		if err := json.Unmarshal(data, &pair); err != nil || len(pair) != 2 {
+			return fmt.Errorf("account data: unexpected %s", data)
+		}
+		encoded, d.Encoding = pair[0], Encoding(pair[1])
+	}
+
+	var err error
+	switch d.Encoding {
+	case EncodingBase58:
+		d.Bytes, err = DecodeBase58(encoded)
+	case EncodingBase64, "base64+zstd":
+		d.Bytes, err = base64.StdEncoding.DecodeString(encoded)
+	default:
+		err = fmt.Errorf("unsupported encoding %q", d.Encoding)
+	}
+	if err != nil {
+		return fmt.Errorf("account data: %w", err)
+	}
+	return nil
+}
+
+// TransactionReturnData is the data a transaction's last returning
+// instruction passed to set_return_data.
+type TransactionReturnData struct {
+	ProgramID Pubkey
+	Data      []byte
+}
+
+type returnDataJSON struct {
+	ProgramID Pubkey   `json:"programId"`
+	Data      []string `json:"data"`
+}
+
+func (r TransactionReturnData) MarshalJSON() ([]byte, error) {
+	return json.Marshal(returnDataJSON{
+		ProgramID: r.ProgramID,
+		Data:      []string{base64.StdEncoding.EncodeToString(r.Data), string(EncodingBase64)},
+	})
+}
+
+func (r *TransactionReturnData) UnmarshalJSON(data []byte) error {
+	var raw returnDataJSON
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return err
+	}
+	if len(raw.Data) != 2 || raw.Data[1] != string(EncodingBase64) {
+		return errors.New("return data: expected [data, \"base64\"]")
+	}
+	b, err := base64.StdEncoding.DecodeString(raw.Data[0])
+	if err != nil {
+		return fmt.Errorf("return data: %w", err)
+	}
+	*r = TransactionReturnData{ProgramID: raw.ProgramID, Data: b}
+	return nil
+}
+
+// InnerInstructions lists the instructions invoked through cross-program
+// invocation by the top-level instruction at Index. T is the form the node
+// sends them in, which depends on the method and encoding.
+type InnerInstructions[T any] struct {
+	Index        int64 `json:"index"`
+	Instructions []T   `json:"instructions"`
+}
+
+// InnerInstruction is an inner instruction in compiled form. Its program and
+// accounts index the transaction's account keys, followed by any addresses
+// loaded from lookup tables, and Data is base58-encoded. StackHeight is the
+// invocation depth, starting at 1 for top-level instructions.
+type InnerInstruction struct {
+	ProgramIDIndex int64   `json:"programIdIndex"`
+	Accounts       []int64 `json:"accounts"`
+	Data           string  `json:"data"`
+	StackHeight    *int64  `json:"stackHeight,omitempty"`
+}
+
+// ParsedInnerInstruction is an inner instruction in parsed form, as
+// simulateTransaction always returns them. Instructions of programs the node
+// has a parser for carry Program and Parsed; the rest are partially decoded,
+// with Accounts and base58-encoded Data instead.
+type ParsedInnerInstruction struct {
+	ProgramID   Pubkey          `json:"programId"`
+	Program     string          `json:"program,omitempty"`
+	Parsed      json.RawMessage `json:"parsed,omitempty"`
+	Accounts    []Pubkey        `json:"accounts,omitempty"`
+	Data        string          `json:"data,omitempty"`
+	StackHeight *int64          `json:"stackHeight,omitempty"`
+}
+
+// EncodedInnerInstruction is an inner instruction from a transaction's meta,
+// in the form its encoding gives: Compiled for json, base58 and base64, and
+// Parsed for jsonParsed. Exactly one is set.
+type EncodedInnerInstruction struct {
+	Compiled *InnerInstruction
+	Parsed   *ParsedInnerInstruction
+}
+
+func (i EncodedInnerInstruction) MarshalJSON() ([]byte, error) {
+	if i.Compiled != nil {
+		return json.Marshal(i.Compiled)
+	}
+	return json.Marshal(i.Parsed)
+}
+
+func (i *EncodedInnerInstruction) UnmarshalJSON(data []byte) error {
+	var probe struct {
+		ProgramIDIndex *int64 `json:"programIdIndex"`
+	}
+	if err := json.Unmarshal(data, &probe); err != nil {
+		return fmt.Errorf("inner instruction: %w", err)
+	}
+	*i = EncodedInnerInstruction{}
+	if probe.ProgramIDIndex != nil {
+		i.Compiled = new(InnerInstruction)
+		return json.Unmarshal(data, i.Compiled)
+	}
+	i.Parsed = new(ParsedInnerInstruction)
+	return json.Unmarshal(data, i.Parsed)
+}
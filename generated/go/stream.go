@@ -0,0 +1,243 @@
+package solana
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// GetProgramAccountsStream is GetProgramAccounts for programs with too many
+// accounts to decode at once. The response body is walked token by token and
+// fn is called once per account as it is decoded.
+//
+// The response is matched to the request by its id and jsonrpc version. When
+// the server sends both ahead of the result, a mismatched response fails
+// before fn is called. Agave validators send the id after the result, so the
+// check can only happen once the accounts have been streamed: fn may then
+// have seen accounts from a response that ends up failing with
+// ErrResponseMismatch, and callers should treat what fn saw as void when that
+// error is returned. Holding the accounts back instead would make memory use
+// grow with the result.
+//
+// If fn returns an error the stream is abandoned and that error is returned.
+// Responses requested with WithContext are accepted; only their value array is
+// streamed.
+//
+// The call goes through the client's interceptors like any other, so headers
+// and endpoint rewrites apply, but the response they see carries no Result:
+// the accounts have already gone to fn by the time it is returned. An
+// interceptor that answers without calling next has its Result streamed
+// instead, and one that calls next more than once streams the accounts to fn
+// each time. Rate limits apply and the stream holds an in-flight slot until it
+// returns; response size limits and the body read timeout do not.
+func (c *Client) GetProgramAccountsStream(ctx context.Context, programId Pubkey, config *GetProgramAccountsConfig, fn func(ProgramAccount) error) error {
+	params := make([]interface{}, 0)
+	params = append(params, programId)
+	config = withDefaults(c.defaults(ctx, "getProgramAccounts"), config)
+	if config != nil {
+		params = append(params, *config)
+	}
+
+	streamed := false
+	stream := func(ctx context.Context, r *Request) (*RawResponse, error) {
+		streamed = true
+		return c.streamProgramAccounts(ctx, r, fn)
+	}
+	resp, err := chainInterceptors(c.interceptors, stream)(ctx, c.newRequest("getProgramAccounts", params))
+	if err != nil {
+		return err
+	}
+	if resp.Error != nil {
+		return resp.Error
+	}
+	if streamed || len(resp.Result) == 0 {
+		return nil
+	}
+	return streamProgramAccounts(json.NewDecoder(bytes.NewReader(resp.Result)), fn)
+}
+
+// streamProgramAccounts sends r and streams the accounts in its response to
+// fn. JSON-RPC errors are returned in the response, as from roundTrip, so
+// interceptors can tell them apart from transport failures.
+func (c *Client) streamProgramAccounts(ctx context.Context, r *Request, fn func(ProgramAccount) error) (*RawResponse, error) {
+	if c.limiter != nil {
+		release, err := c.limiter.acquire(ctx, r.Method)
+		if err != nil {
+			return nil, err
+		}
+		defer release()
+	}
+
+	req, err := c.newHTTPRequest(ctx, r)
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := c.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("do request: %w", redactURLError(err))
+	}
+	defer resp.Body.Close()
+
+	body, err := c.compression.decode(resp)
+	if err != nil {
+		return nil, err
+	}
+	defer body.Close()
+
+	raw := &RawResponse{ID: r.ID, StatusCode: resp.StatusCode, Header: resp.Header}
+	err = decodeProgramAccountsStream(r, json.NewDecoder(body), fn)
+	raw.BodySize = body.wire.n
+	var rpcErr *RPCError
+	if errors.As(err, &rpcErr) {
+		raw.Error = rpcErr
+		return raw, nil
+	}
+	if err != nil {
+		if statusErr := checkStatus(r, resp.StatusCode, nil, nil); statusErr != nil {
+			return nil, statusErr
+		}
+		return nil, err
+	}
+	return raw, nil
+}
+
+// decodeProgramAccountsStream walks a JSON-RPC response envelope and hands each
+// element of the result array to fn. The envelope's id and jsonrpc version are
+// checked before the result when they precede it, and otherwise once it has
+// been streamed.
+func decodeProgramAccountsStream(req *Request, dec *json.Decoder, fn func(ProgramAccount) error) error {
+	if err := expectDelim(dec, '{'); err != nil {
+		return fmt.Errorf("decode response: %w", err)
+	}
+
+	var envelope rpcResponse
+	sawResult := false
+	for dec.More() {
+		key, err := dec.Token()
+		if err != nil {
+			return fmt.Errorf("decode response: %w", err)
+		}
+
+		switch key {
+		case "result":
+			sawResult = true
+			if envelope.JsonRPC != "" && envelope.ID != nil {
+				if err := checkResponse(req, &envelope); err != nil {
+					return err
+				}
+			}
+			if err := streamProgramAccounts(dec, fn); err != nil {
+				return err
+			}
+		case "jsonrpc":
+			if err := dec.Decode(&envelope.JsonRPC); err != nil {
+				return fmt.Errorf("decode response: %w", err)
+			}
+		case "id":
+			if err := dec.Decode(&envelope.ID); err != nil {
+				return fmt.Errorf("decode response: %w", err)
+			}
+		case "error":
+			var rpcErr *RPCError
+			if err := dec.Decode(&rpcErr); err != nil {
+				return fmt.Errorf("decode response: %w", err)
+			}
+			if rpcErr != nil {
+				return rpcErr
+			}
+		default:
+			if err := skipValue(dec); err != nil {
+				return fmt.Errorf("decode response: %w", err)
+			}
+		}
+	}
+
+	if !sawResult {
+		return errors.New("decode response: missing result")
+	}
+	return checkResponse(req, &envelope)
+}
+
+// streamProgramAccounts consumes the result value, which is either the account
+// array itself, a {context, value} object wrapping it, or null.
+func streamProgramAccounts(dec *json.Decoder, fn func(ProgramAccount) error) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return fmt.Errorf("decode result: %w", err)
+	}
+
+	switch tok {
+	case nil:
+		return nil
+	case json.Delim('['):
+		for dec.More() {
+			var account ProgramAccount
+			if err := dec.Decode(&account); err != nil {
+				return fmt.Errorf("decode account: %w", err)
+			}
+			if err := fn(account); err != nil {
+				return err
+			}
+		}
+		if err := expectDelim(dec, ']'); err != nil {
+			return fmt.Errorf("decode result: %w", err)
+		}
+		return nil
+	case json.Delim('{'):
+		for dec.More() {
+			key, err := dec.Token()
+			if err != nil {
+				return fmt.Errorf("decode result: %w", err)
+			}
+			if key == "value" {
+				if err := streamProgramAccounts(dec, fn); err != nil {
+					return err
+				}
+				continue
+			}
+			if err := skipValue(dec); err != nil {
+				return fmt.Errorf("decode result: %w", err)
+			}
+		}
+		if err := expectDelim(dec, '}'); err != nil {
+			return fmt.Errorf("decode result: %w", err)
+		}
+		return nil
+	default:
+		return fmt.Errorf("decode result: unexpected token %v", tok)
+	}
+}
+
+func expectDelim(dec *json.Decoder, want json.Delim) error {
+	tok, err := dec.Token()
+	if err != nil {
+		return err
+	}
+	if tok != want {
+		return fmt.Errorf("expected %v, got %v", want, tok)
+	}
+	return nil
+}
+
+// skipValue discards the next JSON value without materialising nested objects.
+func skipValue(dec *json.Decoder) error {
+	depth := 0
+	for {
+		tok, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		switch tok {
+		case json.Delim('{'), json.Delim('['):
+			depth++
+		case json.Delim('}'), json.Delim(']'):
+			depth--
+		}
+		if depth == 0 {
+			return nil
+		}
+	}
+}
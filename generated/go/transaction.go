@@ -0,0 +1,389 @@
+package solana
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+const (
+	// messageVersionPrefix marks a versioned message; the low bits hold the
+	// version. Legacy messages start with the header instead, whose first
+	// byte never has the high bit set.
+	messageVersionPrefix = 0x80
+
+	pubkeyLength    = 32
+	signatureLength = 64
+)
+
+// Transaction is a transaction in wire format: signatures over a message.
+// Use UnmarshalBinary and MarshalBinary to convert it to and from the bytes
+// SendTransaction and SimulateTransaction take, base64-encoded.
+type Transaction struct {
+	Signatures []Signature
+	Message    Message
+}
+
+// Message is the signed part of a transaction.
+type Message struct {
+	// Versioned is false for legacy messages. Version is only meaningful
+	// for versioned ones, and address table lookups only exist there.
+	Versioned bool
+	Version   uint8
+
+	Header              MessageHeader
+	AccountKeys         []Pubkey
+	RecentBlockhash     Hash
+	Instructions        []CompiledInstruction
+	AddressTableLookups []AddressTableLookup
+}
+
+// MessageHeader says how many of a message's account keys sign it and how
+// many of the signers and non-signers are read-only. Keys are ordered
+// writable signers, read-only signers, writable non-signers, read-only
+// non-signers.
+type MessageHeader struct {
+	NumRequiredSignatures       uint8
+	NumReadonlySignedAccounts   uint8
+	NumReadonlyUnsignedAccounts uint8
+}
+
+// CompiledInstruction is an Instruction whose program and accounts are
+// indexes into the message's account keys, followed by any addresses loaded
+// from lookup tables.
+type CompiledInstruction struct {
+	ProgramIDIndex uint8
+	Accounts       []uint8
+	Data           []byte
+}
+
+// AddressTableLookup loads accounts from an address lookup table.
+type AddressTableLookup struct {
+	AccountKey      Pubkey
+	WritableIndexes []uint8
+	ReadonlyIndexes []uint8
+}
+
+// MarshalBinary encodes tx in wire format.
+func (tx *Transaction) MarshalBinary() ([]byte, error) {
+	var b []byte
+	b = appendShortVec(b, len(tx.Signatures))
+	for _, sig := range tx.Signatures {
+		raw, err := decodeFixed(sig, signatureLength)
+		if err != nil {
+			return nil, fmt.Errorf("signature: %w", err)
+		}
+		b = append(b, raw...)
+	}
+	msg, err := tx.Message.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	return append(b, msg...), nil
+}
+
+// UnmarshalBinary decodes a wire-format transaction.
+func (tx *Transaction) UnmarshalBinary(data []byte) error {
+	r := &wireReader{b: data}
+	n, err := r.shortVec()
+	if err != nil {
+		return fmt.Errorf("signatures: %w", err)
+	}
+	tx.Signatures = make([]Signature, n)
+	for i := range tx.Signatures {
+		raw, err := r.bytes(signatureLength)
+		if err != nil {
+			return fmt.Errorf("signature %d: %w", i, err)
+		}
+		tx.Signatures[i] = EncodeBase58(raw)
+	}
+	return tx.Message.UnmarshalBinary(r.b[r.off:])
+}
+
+// MarshalBinary encodes m in wire format, the bytes a transaction's
+// signatures sign.
+func (m *Message) MarshalBinary() ([]byte, error) {
+	var b []byte
+	if m.Versioned {
+		b = append(b, messageVersionPrefix|m.Version)
+	}
+	b = append(b, m.Header.NumRequiredSignatures, m.Header.NumReadonlySignedAccounts, m.Header.NumReadonlyUnsignedAccounts)
+
+	b = appendShortVec(b, len(m.AccountKeys))
+	for _, key := range m.AccountKeys {
+		raw, err := decodeFixed(key, pubkeyLength)
+		if err != nil {
+			return nil, fmt.Errorf("account key: %w", err)
+		}
+		b = append(b, raw...)
+	}
+	raw, err := decodeFixed(m.RecentBlockhash, pubkeyLength)
+	if err != nil {
+		return nil, fmt.Errorf("recent blockhash: %w", err)
+	}
+	b = append(b, raw...)
+
+	b = appendShortVec(b, len(m.Instructions))
+	for _, ix := range m.Instructions {
+		b = append(b, ix.ProgramIDIndex)
+		b = appendShortVec(b, len(ix.Accounts))
+		b = append(b, ix.Accounts...)
+		b = appendShortVec(b, len(ix.Data))
+		b = append(b, ix.Data...)
+	}
+
+	if !m.Versioned {
+		if len(m.AddressTableLookups) > 0 {
+			return nil, errors.New("legacy message has address table lookups")
+		}
+		return b, nil
+	}
+	b = appendShortVec(b, len(m.AddressTableLookups))
+	for _, lookup := range m.AddressTableLookups {
+		raw, err := decodeFixed(lookup.AccountKey, pubkeyLength)
+		if err != nil {
+			return nil, fmt.Errorf("lookup table key: %w", err)
+		}
+		b = append(b, raw...)
+		b = appendShortVec(b, len(lookup.WritableIndexes))
+		b = append(b, lookup.WritableIndexes...)
+		b = appendShortVec(b, len(lookup.ReadonlyIndexes))
+		b = append(b, lookup.ReadonlyIndexes...)
+	}
+	return b, nil
+}
+
+// UnmarshalBinary decodes a wire-format message, legacy or versioned.
+func (m *Message) UnmarshalBinary(data []byte) error {
+	r := &wireReader{b: data}
+	*m = Message{}
+
+	first, err := r.byte()
+	if err != nil {
+		return fmt.Errorf("message: %w", err)
+	}
+	if first&messageVersionPrefix != 0 {
+		m.Versioned, m.Version = true, first&^messageVersionPrefix
+		if m.Version != 0 {
+			return fmt.Errorf("unsupported message version %d", m.Version)
+		}
+		if first, err = r.byte(); err != nil {
+			return fmt.Errorf("message header: %w", err)
+		}
+	}
+	header, err := r.bytes(2)
+	if err != nil {
+		return fmt.Errorf("message header: %w", err)
+	}
+	m.Header = MessageHeader{first, header[0], header[1]}
+
+	n, err := r.shortVec()
+	if err != nil {
+		return fmt.Errorf("account keys: %w", err)
+	}
+	m.AccountKeys = make([]Pubkey, n)
+	for i := range m.AccountKeys {
+		raw, err := r.bytes(pubkeyLength)
+		if err != nil {
+			return fmt.Errorf("account key %d: %w", i, err)
+		}
+		m.AccountKeys[i] = EncodeBase58(raw)
+	}
+	raw, err := r.bytes(pubkeyLength)
+	if err != nil {
+		return fmt.Errorf("recent blockhash: %w", err)
+	}
+	m.RecentBlockhash = EncodeBase58(raw)
+
+	if n, err = r.shortVec(); err != nil {
+		return fmt.Errorf("instructions: %w", err)
+	}
+	m.Instructions = make([]CompiledInstruction, n)
+	for i := range m.Instructions {
+		ix := &m.Instructions[i]
+		if ix.ProgramIDIndex, err = r.byte(); err != nil {
+			return fmt.Errorf("instruction %d: %w", i, err)
+		}
+		if ix.Accounts, err = r.shortVecBytes(); err != nil {
+			return fmt.Errorf("instruction %d accounts: %w", i, err)
+		}
+		if ix.Data, err = r.shortVecBytes(); err != nil {
+			return fmt.Errorf("instruction %d data: %w", i, err)
+		}
+	}
+
+	if m.Versioned {
+		if n, err = r.shortVec(); err != nil {
+			return fmt.Errorf("address table lookups: %w", err)
+		}
+		m.AddressTableLookups = make([]AddressTableLookup, n)
+		for i := range m.AddressTableLookups {
+			lookup := &m.AddressTableLookups[i]
+			raw, err := r.bytes(pubkeyLength)
+			if err != nil {
+				return fmt.Errorf("lookup %d: %w", i, err)
+			}
+			lookup.AccountKey = EncodeBase58(raw)
+			if lookup.WritableIndexes, err = r.shortVecBytes(); err != nil {
+				return fmt.Errorf("lookup %d: %w", i, err)
+			}
+			if lookup.ReadonlyIndexes, err = r.shortVecBytes(); err != nil {
+				return fmt.Errorf("lookup %d: %w", i, err)
+			}
+		}
+	}
+
+	if r.off != len(r.b) {
+		return fmt.Errorf("message: %d trailing bytes", len(r.b)-r.off)
+	}
+	return nil
+}
+
+// ResolvedAccountKeys returns the keys compiled instructions index: the
+// static account keys followed by the writable and then the read-only
+// addresses loaded from lookup tables, as reported in
+// TransactionMeta.LoadedAddresses. loaded may be nil for legacy messages.
+func (m *Message) ResolvedAccountKeys(loaded *LoadedAddresses) []Pubkey {
+	keys := append([]Pubkey(nil), m.AccountKeys...)
+	if loaded != nil {
+		keys = append(keys, loaded.Writable...)
+		keys = append(keys, loaded.Readonly...)
+	}
+	return keys
+}
+
+// EncodedTransaction is a transaction as returned by GetTransaction. Binary
+// encodings are decoded into Transaction; json and jsonParsed transactions
+// are kept in JSON, since their shape depends on the encoding.
+type EncodedTransaction struct {
+	Transaction *Transaction
+	Encoding    TransactionEncoding
+	JSON        json.RawMessage
+}
+
+func (t EncodedTransaction) MarshalJSON() ([]byte, error) {
+	if t.Transaction == nil {
+		if t.JSON == nil {
+			return []byte("null"), nil
+		}
+		return t.JSON, nil
+	}
+	wire, err := t.Transaction.MarshalBinary()
+	if err != nil {
+		return nil, err
+	}
+	if t.Encoding == TransactionEncodingBase58 {
+		return json.Marshal([]string{EncodeBase58(wire), string(t.Encoding)})
+	}
+	return json.Marshal([]string{base64.StdEncoding.EncodeToString(wire), string(TransactionEncodingBase64)})
+}
+
+func (t *EncodedTransaction) UnmarshalJSON(data []byte) error {
+	*t = EncodedTransaction{}
+	if string(data) == "null" {
+		return nil
+	}
+	if len(data) > 0 && data[0] == '{' {
+		// json and jsonParsed look alike; callers know which they asked for.
+		t.Encoding = TransactionEncodingJson
+		t.JSON = append(json.RawMessage(nil), data...)
+		return nil
+	}
+
+	var pair []string
+	if err := json.Unmarshal(data, &pair); err != nil || len(pair) != 2 {
+		return fmt.Errorf("transaction: unexpected %s", data)
+	}
+	t.Encoding = TransactionEncoding(pair[1])
+	var wire []byte
+	var err error
+	switch t.Encoding {
+	case TransactionEncodingBase58:
+		wire, err = DecodeBase58(pair[0])
+	case TransactionEncodingBase64:
+		wire, err = base64.StdEncoding.DecodeString(pair[0])
+	default:
+		err = fmt.Errorf("unsupported encoding %q", t.Encoding)
+	}
+	if err != nil {
+		return fmt.Errorf("transaction: %w", err)
+	}
+	t.Transaction = new(Transaction)
+	if err := t.Transaction.UnmarshalBinary(wire); err != nil {
+		return fmt.Errorf("transaction: %w", err)
+	}
+	return nil
+}
+
+// decodeFixed decodes a base58 key, signature or hash of exactly n bytes.
+func decodeFixed(s string, n int) ([]byte, error) {
+	b, err := DecodeBase58(s)
+	if err != nil {
+		return nil, err
+	}
+	if len(b) != n {
+		return nil, fmt.Errorf("%q decodes to %d bytes, want %d", s, len(b), n)
+	}
+	return b, nil
+}
+
+// appendShortVec appends n as a compact-u16: 7 bits per byte, low bits first.
+func appendShortVec(b []byte, n int) []byte {
+	for {
+		v := byte(n & 0x7f)
+		n >>= 7
+		if n == 0 {
+			return append(b, v)
+		}
+		b = append(b, v|0x80)
+	}
+}
+
+type wireReader struct {
+	b   []byte
+	off int
+}
+
+func (r *wireReader) byte() (byte, error) {
+	if r.off >= len(r.b) {
+		return 0, errors.New("unexpected end of data")
+	}
+	r.off++
+	return r.b[r.off-1], nil
+}
+
+func (r *wireReader) bytes(n int) ([]byte, error) {
+	if n > len(r.b)-r.off {
+		return nil, errors.New("unexpected end of data")
+	}
+	r.off += n
+	return r.b[r.off-n : r.off], nil
+}
+
+func (r *wireReader) shortVec() (int, error) {
+	n := 0
+	for shift := 0; shift < 21; shift += 7 {
+		v, err := r.byte()
+		if err != nil {
+			return 0, err
+		}
+		n |= int(v&0x7f) << shift
+		if v&0x80 == 0 {
+			return n, nil
+		}
+	}
+	return 0, errors.New("compact-u16 too long")
+}
+
+func (r *wireReader) shortVecBytes() ([]byte, error) {
+	n, err := r.shortVec()
+	if err != nil {
+		return nil, err
+	}
+	b, err := r.bytes(n)
+	if err != nil {
+		return nil, err
+	}
+	return append([]byte(nil), b...), nil
+}
@@ -2,6 +2,13 @@ package solana
 
 // Auto-generated Solana RPC Types
 
+// Response is the {context, value} envelope returned by most account and bank
+// queries.
+type Response[T any] struct {
+	Context RpcContext `json:"context"`
+	Value   T          `json:"value"`
+}
+
 type Pubkey = string
 
 type Signature = string
@@ -41,6 +48,15 @@ type CommitmentConfig struct {
 	MinContextSlot int64 `json:"minContextSlot,omitempty"`
 }
 
+func (c *CommitmentConfig) applyDefaults(d callDefaults) {
+	if c.Commitment == "" {
+		c.Commitment = d.commitment
+	}
+	if c.MinContextSlot < d.minContextSlot {
+		c.MinContextSlot = d.minContextSlot
+	}
+}
+
 type GetAccountInfoConfig struct {
 	Commitment Commitment `json:"commitment,omitempty"`
 	Encoding Encoding `json:"encoding,omitempty"`
@@ -48,6 +64,15 @@ type GetAccountInfoConfig struct {
 	MinContextSlot int64 `json:"minContextSlot,omitempty"`
 }
 
+func (c *GetAccountInfoConfig) applyDefaults(d callDefaults) {
+	if c.Commitment == "" {
+		c.Commitment = d.commitment
+	}
+	if c.MinContextSlot < d.minContextSlot {
+		c.MinContextSlot = d.minContextSlot
+	}
+}
+
 type DataSlice struct {
 	Offset int64 `json:"offset,omitempty"`
 	Length int64 `json:"length,omitempty"`
@@ -61,26 +86,17 @@ type RpcContext struct {
 type AccountInfo struct {
 	Lamports int64 `json:"lamports,omitempty"`
 	Owner Pubkey `json:"owner,omitempty"`
-	Data map[string]interface{} `json:"data,omitempty"`
+	Data AccountData `json:"data,omitempty"`
 	Executable bool `json:"executable,omitempty"`
 	RentEpoch int64 `json:"rentEpoch,omitempty"`
 	Space int64 `json:"space,omitempty"`
 }
 
-type AccountInfoResponse struct {
-	Context RpcContext `json:"context,omitempty"`
-	Value AccountInfo `json:"value,omitempty"`
-}
+type AccountInfoResponse = Response[AccountInfo]
 
-type RpcResponseU64 struct {
-	Context RpcContext `json:"context,omitempty"`
-	Value int64 `json:"value,omitempty"`
-}
+type RpcResponseU64 = Response[int64]
 
-type RpcResponseBool struct {
-	Context RpcContext `json:"context,omitempty"`
-	Value bool `json:"value,omitempty"`
-}
+type RpcResponseBool = Response[bool]
 
 type GetBlockConfig struct {
 	Encoding TransactionEncoding `json:"encoding,omitempty"`
@@ -90,6 +106,12 @@ type GetBlockConfig struct {
 	MaxSupportedTransactionVersion int64 `json:"maxSupportedTransactionVersion,omitempty"`
 }
 
+func (c *GetBlockConfig) applyDefaults(d callDefaults) {
+	if c.Commitment == "" {
+		c.Commitment = d.commitment
+	}
+}
+
 type Block struct {
 	Blockhash Hash `json:"blockhash,omitempty"`
 	PreviousBlockhash Hash `json:"previousBlockhash,omitempty"`
@@ -112,11 +134,14 @@ type GetBlockProductionConfig struct {
 	Identity Pubkey `json:"identity,omitempty"`
 }
 
-type BlockProduction struct {
-	Context RpcContext `json:"context,omitempty"`
-	Value map[string]interface{} `json:"value,omitempty"`
+func (c *GetBlockProductionConfig) applyDefaults(d callDefaults) {
+	if c.Commitment == "" {
+		c.Commitment = d.commitment
+	}
 }
 
+type BlockProduction = Response[map[string]interface{}]
+
 type ClusterNode struct {
 	Pubkey Pubkey `json:"pubkey,omitempty"`
 	Gossip string `json:"gossip,omitempty"`
@@ -170,6 +195,15 @@ type GetInflationRewardConfig struct {
 	MinContextSlot int64 `json:"minContextSlot,omitempty"`
 }
 
+func (c *GetInflationRewardConfig) applyDefaults(d callDefaults) {
+	if c.Commitment == "" {
+		c.Commitment = d.commitment
+	}
+	if c.MinContextSlot < d.minContextSlot {
+		c.MinContextSlot = d.minContextSlot
+	}
+}
+
 type InflationReward struct {
 	Epoch int64 `json:"epoch,omitempty"`
 	EffectiveSlot Slot `json:"effectiveSlot,omitempty"`
@@ -183,14 +217,19 @@ type GetLargestAccountsConfig struct {
 	Filter string `json:"filter,omitempty"`
 }
 
-type RpcResponseLargestAccounts struct {
-	Context RpcContext `json:"context,omitempty"`
-	Value []map[string]interface{} `json:"value,omitempty"`
+func (c *GetLargestAccountsConfig) applyDefaults(d callDefaults) {
+	if c.Commitment == "" {
+		c.Commitment = d.commitment
+	}
 }
 
-type LatestBlockhashResponse struct {
-	Context RpcContext `json:"context,omitempty"`
-	Value map[string]interface{} `json:"value,omitempty"`
+type RpcResponseLargestAccounts = Response[[]map[string]interface{}]
+
+type LatestBlockhashResponse = Response[LatestBlockhash]
+
+type LatestBlockhash struct {
+	Blockhash Hash `json:"blockhash,omitempty"`
+	LastValidBlockHeight int64 `json:"lastValidBlockHeight,omitempty"`
 }
 
 type GetLeaderScheduleConfig struct {
@@ -198,12 +237,15 @@ type GetLeaderScheduleConfig struct {
 	Identity Pubkey `json:"identity,omitempty"`
 }
 
+func (c *GetLeaderScheduleConfig) applyDefaults(d callDefaults) {
+	if c.Commitment == "" {
+		c.Commitment = d.commitment
+	}
+}
+
 type LeaderSchedule = map[string][]int64
 
-type MultipleAccountsResponse struct {
-	Context RpcContext `json:"context,omitempty"`
-	Value []AccountInfo `json:"value,omitempty"`
-}
+type MultipleAccountsResponse = Response[[]AccountInfo]
 
 type GetProgramAccountsConfig struct {
 	Commitment Commitment `json:"commitment,omitempty"`
@@ -214,9 +256,13 @@ type GetProgramAccountsConfig struct {
 	MinContextSlot int64 `json:"minContextSlot,omitempty"`
 }
 
-type AccountFilter struct {
-	Memcmp map[string]interface{} `json:"memcmp,omitempty"`
-	DataSize int64 `json:"dataSize,omitempty"`
+func (c *GetProgramAccountsConfig) applyDefaults(d callDefaults) {
+	if c.Commitment == "" {
+		c.Commitment = d.commitment
+	}
+	if c.MinContextSlot < d.minContextSlot {
+		c.MinContextSlot = d.minContextSlot
+	}
 }
 
 type ProgramAccount struct {
@@ -241,10 +287,7 @@ type GetSignatureStatusesConfig struct {
 	SearchTransactionHistory bool `json:"searchTransactionHistory,omitempty"`
 }
 
-type SignatureStatusesResponse struct {
-	Context RpcContext `json:"context,omitempty"`
-	Value []SignatureStatus `json:"value,omitempty"`
-}
+type SignatureStatusesResponse = Response[[]SignatureStatus]
 
 type SignatureStatus struct {
 	Slot Slot `json:"slot,omitempty"`
@@ -261,6 +304,15 @@ type GetSignaturesForAddressConfig struct {
 	MinContextSlot int64 `json:"minContextSlot,omitempty"`
 }
 
+func (c *GetSignaturesForAddressConfig) applyDefaults(d callDefaults) {
+	if c.Commitment == "" {
+		c.Commitment = d.commitment
+	}
+	if c.MinContextSlot < d.minContextSlot {
+		c.MinContextSlot = d.minContextSlot
+	}
+}
+
 type SignatureInfo struct {
 	Signature Signature `json:"signature,omitempty"`
 	Slot Slot `json:"slot,omitempty"`
@@ -275,15 +327,15 @@ type GetSupplyConfig struct {
 	ExcludeNonCirculatingAccountsList bool `json:"excludeNonCirculatingAccountsList,omitempty"`
 }
 
-type SupplyResponse struct {
-	Context RpcContext `json:"context,omitempty"`
-	Value map[string]interface{} `json:"value,omitempty"`
+func (c *GetSupplyConfig) applyDefaults(d callDefaults) {
+	if c.Commitment == "" {
+		c.Commitment = d.commitment
+	}
 }
 
-type TokenBalanceResponse struct {
-	Context RpcContext `json:"context,omitempty"`
-	Value TokenAmount `json:"value,omitempty"`
-}
+type SupplyResponse = Response[map[string]interface{}]
+
+type TokenBalanceResponse = Response[TokenAmount]
 
 type TokenAmount struct {
 	Amount string `json:"amount,omitempty"`
@@ -304,15 +356,18 @@ type GetTokenAccountsConfig struct {
 	MinContextSlot int64 `json:"minContextSlot,omitempty"`
 }
 
-type TokenAccountsResponse struct {
-	Context RpcContext `json:"context,omitempty"`
-	Value []map[string]interface{} `json:"value,omitempty"`
+func (c *GetTokenAccountsConfig) applyDefaults(d callDefaults) {
+	if c.Commitment == "" {
+		c.Commitment = d.commitment
+	}
+	if c.MinContextSlot < d.minContextSlot {
+		c.MinContextSlot = d.minContextSlot
+	}
 }
 
-type TokenLargestAccountsResponse struct {
-	Context RpcContext `json:"context,omitempty"`
-	Value []map[string]interface{} `json:"value,omitempty"`
-}
+type TokenAccountsResponse = Response[[]map[string]interface{}]
+
+type TokenLargestAccountsResponse = Response[[]map[string]interface{}]
 
 type GetTransactionConfig struct {
 	Encoding TransactionEncoding `json:"encoding,omitempty"`
@@ -320,28 +375,39 @@ type GetTransactionConfig struct {
 	MaxSupportedTransactionVersion int64 `json:"maxSupportedTransactionVersion,omitempty"`
 }
 
+func (c *GetTransactionConfig) applyDefaults(d callDefaults) {
+	if c.Commitment == "" {
+		c.Commitment = d.commitment
+	}
+}
+
 type TransactionResponse struct {
 	Slot Slot `json:"slot,omitempty"`
-	Transaction map[string]interface{} `json:"transaction,omitempty"`
+	Transaction *EncodedTransaction `json:"transaction,omitempty"`
 	BlockTime int64 `json:"blockTime,omitempty"`
 	Meta TransactionMeta `json:"meta,omitempty"`
-	Version map[string]interface{} `json:"version,omitempty"`
+	Version interface{} `json:"version,omitempty"`
 }
 
 type TransactionMeta struct {
-	Err map[string]interface{} `json:"err,omitempty"`
+	Err *TransactionError `json:"err,omitempty"`
 	Fee int64 `json:"fee,omitempty"`
 	PreBalances []int64 `json:"preBalances,omitempty"`
 	PostBalances []int64 `json:"postBalances,omitempty"`
-	InnerInstructions []interface{} `json:"innerInstructions,omitempty"`
+	InnerInstructions []InnerInstructions[EncodedInnerInstruction] `json:"innerInstructions,omitempty"`
 	PreTokenBalances []interface{} `json:"preTokenBalances,omitempty"`
 	PostTokenBalances []interface{} `json:"postTokenBalances,omitempty"`
 	LogMessages []string `json:"logMessages,omitempty"`
 	Rewards []interface{} `json:"rewards,omitempty"`
-	LoadedAddresses map[string]interface{} `json:"loadedAddresses,omitempty"`
+	LoadedAddresses *LoadedAddresses `json:"loadedAddresses,omitempty"`
 	ComputeUnitsConsumed int64 `json:"computeUnitsConsumed,omitempty"`
 }
 
+type LoadedAddresses struct {
+	Writable []Pubkey `json:"writable,omitempty"`
+	Readonly []Pubkey `json:"readonly,omitempty"`
+}
+
 type Version struct {
 	SolanaCore string `json:"solana-core,omitempty"`
 	FeatureSet int64 `json:"feature-set,omitempty"`
@@ -354,6 +420,12 @@ type GetVoteAccountsConfig struct {
 	DelinquentSlotDistance int64 `json:"delinquentSlotDistance,omitempty"`
 }
 
+func (c *GetVoteAccountsConfig) applyDefaults(d callDefaults) {
+	if c.Commitment == "" {
+		c.Commitment = d.commitment
+	}
+}
+
 type VoteAccountsResponse struct {
 	Current []VoteAccount `json:"current,omitempty"`
 	Delinquent []VoteAccount `json:"delinquent,omitempty"`
@@ -378,17 +450,46 @@ type SendTransactionConfig struct {
 	MinContextSlot int64 `json:"minContextSlot,omitempty"`
 }
 
+func (c *SendTransactionConfig) applyDefaults(d callDefaults) {
+	if c.PreflightCommitment == "" {
+		c.PreflightCommitment = d.commitment
+	}
+	if c.MinContextSlot < d.minContextSlot {
+		c.MinContextSlot = d.minContextSlot
+	}
+}
+
 type SimulateTransactionConfig struct {
 	SigVerify bool `json:"sigVerify,omitempty"`
 	Commitment Commitment `json:"commitment,omitempty"`
 	Encoding string `json:"encoding,omitempty"`
 	ReplaceRecentBlockhash bool `json:"replaceRecentBlockhash,omitempty"`
-	Accounts map[string]interface{} `json:"accounts,omitempty"`
+	Accounts *SimulateAccountsConfig `json:"accounts,omitempty"`
 	MinContextSlot int64 `json:"minContextSlot,omitempty"`
 	InnerInstructions bool `json:"innerInstructions,omitempty"`
 }
 
-type SimulateTransactionResponse struct {
-	Context RpcContext `json:"context,omitempty"`
-	Value map[string]interface{} `json:"value,omitempty"`
+type SimulateAccountsConfig struct {
+	Encoding Encoding `json:"encoding,omitempty"`
+	Addresses []Pubkey `json:"addresses,omitempty"`
+}
+
+func (c *SimulateTransactionConfig) applyDefaults(d callDefaults) {
+	if c.Commitment == "" {
+		c.Commitment = d.commitment
+	}
+	if c.MinContextSlot < d.minContextSlot {
+		c.MinContextSlot = d.minContextSlot
+	}
+}
+
+type SimulateTransactionResponse = Response[SimulateTransactionResult]
+
+type SimulateTransactionResult struct {
+	Err *TransactionError `json:"err,omitempty"`
+	Logs []string `json:"logs,omitempty"`
+	Accounts []*AccountInfo `json:"accounts,omitempty"`
+	UnitsConsumed int64 `json:"unitsConsumed,omitempty"`
+	ReturnData *TransactionReturnData `json:"returnData,omitempty"`
+	InnerInstructions []InnerInstructions[ParsedInnerInstruction] `json:"innerInstructions,omitempty"`
 }
@@ -40,6 +40,42 @@ interface Schema {
   additionalProperties?: Schema;
 }
 
+// Schemas whose Go definitions are written by hand next to the generated
+// files, usually to give an object the spec leaves untyped a concrete shape.
+const handWrittenTypes = new Set(['AccountFilter']);
+
+// Envelopes whose inline value object is emitted as a named struct, so callers
+// get typed fields instead of a map.
+const envelopeValueTypes: Record<string, string> = {
+  LatestBlockhashResponse: 'LatestBlockhash',
+  SimulateTransactionResponse: 'SimulateTransactionResult',
+};
+
+// Struct fields whose Go type replaces the one derived from the spec, keyed
+// by "Struct.property". Used where the spec's schema is wrong or too loose.
+const goPropertyTypes: Record<string, string> = {
+  'AccountInfo.data': 'AccountData',
+  'SimulateTransactionResult.err': '*TransactionError',
+  // Accounts that do not exist come back as null.
+  'SimulateTransactionResult.accounts': '[]*AccountInfo',
+  'SimulateTransactionResult.returnData': '*TransactionReturnData',
+  // Always in parsed form, whatever the transaction's encoding.
+  'SimulateTransactionResult.innerInstructions': '[]InnerInstructions[ParsedInnerInstruction]',
+  'TransactionResponse.transaction': '*EncodedTransaction',
+  // "legacy" or a version number.
+  'TransactionResponse.version': 'interface{}',
+  'TransactionMeta.err': '*TransactionError',
+  // Compiled or parsed, depending on the transaction's encoding.
+  'TransactionMeta.innerInstructions': '[]InnerInstructions[EncodedInnerInstruction]',
+};
+
+// Inline object properties emitted as named structs, keyed like
+// goPropertyTypes. The field is a pointer so an unset struct is omitted.
+const inlineStructTypes: Record<string, string> = {
+  'SimulateTransactionConfig.accounts': 'SimulateAccountsConfig',
+  'TransactionMeta.loadedAddresses': 'LoadedAddresses',
+};
+
 function toGoName(name: string): string {
   return name.charAt(0).toUpperCase() + name.slice(1);
 }
@@ -49,6 +85,46 @@ function toGoFieldName(name: string): string {
   return name.split('-').map(toGoName).join('');
 }
 
+// Names of the commitment properties a client-wide or per-call default may fill in.
+const commitmentFields = ['commitment', 'preflightCommitment'];
+
+// Property a Session raises to the highest slot it has observed.
+const minContextSlotField = 'minContextSlot';
+
+function resolveSchema(schema: Schema, spec: OpenRpcSpec): Schema {
+  if (schema.$ref) {
+    return spec.components.schemas[schema.$ref.split('/').pop()!] ?? schema;
+  }
+  return schema;
+}
+
+function isCommitmentField(schema: Schema | undefined): boolean {
+  return schema?.$ref?.split('/').pop() === 'Commitment';
+}
+
+// Config objects that carry a commitment or minContextSlot get an
+// applyDefaults method so the client can fill in values the caller left unset.
+function acceptsDefaults(schema: Schema, spec: OpenRpcSpec): boolean {
+  const resolved = resolveSchema(schema, spec);
+  return (
+    commitmentFields.some((f) => isCommitmentField(resolved.properties?.[f])) ||
+    resolved.properties?.[minContextSlotField]?.type === 'integer'
+  );
+}
+
+// Objects made of exactly an RpcContext and a value become aliases of the
+// generic Response envelope.
+function envelopeValueSchema(schema: Schema): Schema | undefined {
+  const props = schema.properties;
+  if (!props || Object.keys(props).sort().join(',') !== 'context,value') {
+    return undefined;
+  }
+  if (props.context.$ref?.split('/').pop() !== 'RpcContext') {
+    return undefined;
+  }
+  return props.value;
+}
+
 function schemaToGoType(schema: Schema, spec: OpenRpcSpec): string {
   if (schema.$ref) {
     return schema.$ref.split('/').pop()!;
@@ -82,24 +158,104 @@ function schemaToGoType(schema: Schema, spec: OpenRpcSpec): string {
   }
 }
 
+interface GoMethod {
+  name: string;
+  rpcName: string;
+  summary: string;
+  params: Array<{ name: string; type: string; param: Param }>;
+  returnType: string;
+}
+
+function toGoMethod(method: Method, spec: OpenRpcSpec): GoMethod {
+  const params = method.params.map((p) => {
+    let goType = schemaToGoType(p.schema, spec);
+    if (!p.required) {
+      goType = '*' + goType; // Pointer for optional
+    }
+    return { name: p.name, type: goType, param: p };
+  });
+
+  let returnType = schemaToGoType(method.result.schema, spec);
+  if (returnType === 'interface{}') {
+    returnType = 'json.RawMessage';
+  }
+
+  return { name: toGoName(method.name), rpcName: method.name, summary: method.summary, params, returnType };
+}
+
+function goParamList(m: GoMethod): string {
+  return ['ctx context.Context', ...m.params.map((p) => `${p.name} ${p.type}`)].join(', ');
+}
+
+function goStruct(name: string, properties: Record<string, Schema>, spec: OpenRpcSpec): string[] {
+  const lines = [`type ${name} struct {`];
+  const nested: string[] = [];
+  for (const [fieldName, fieldSchema] of Object.entries(properties)) {
+    const key = `${name}.${fieldName}`;
+    const goFieldName = toGoFieldName(fieldName);
+    let goType = goPropertyTypes[key] ?? schemaToGoType(fieldSchema, spec);
+    if (inlineStructTypes[key] && fieldSchema.properties) {
+      goType = '*' + inlineStructTypes[key];
+      nested.push('', ...goStruct(inlineStructTypes[key], fieldSchema.properties, spec));
+    }
+    const jsonTag = `\`json:"${fieldName},omitempty"\``;
+    lines.push(`\t${goFieldName} ${goType} ${jsonTag}`);
+  }
+  lines.push('}');
+  return [...lines, ...nested];
+}
+
 function generateTypes(spec: OpenRpcSpec): string {
   const lines: string[] = [];
   lines.push('package solana');
   lines.push('');
   lines.push('// Auto-generated Solana RPC Types');
   lines.push('');
+  lines.push('// Response is the {context, value} envelope returned by most account and bank');
+  lines.push('// queries.');
+  lines.push('type Response[T any] struct {');
+  lines.push('\tContext RpcContext `json:"context"`');
+  lines.push('\tValue   T          `json:"value"`');
+  lines.push('}');
+  lines.push('');
 
   for (const [name, schema] of Object.entries(spec.components.schemas)) {
-    if (schema.type === 'object' && schema.properties) {
-      lines.push(`type ${name} struct {`);
-      for (const [fieldName, fieldSchema] of Object.entries(schema.properties)) {
-        const goFieldName = toGoFieldName(fieldName);
-        const goType = schemaToGoType(fieldSchema, spec);
-        const jsonTag = `\`json:"${fieldName},omitempty"\``;
-        lines.push(`\t${goFieldName} ${goType} ${jsonTag}`);
-      }
-      lines.push('}');
+    if (handWrittenTypes.has(name)) {
+      continue;
+    }
+    const valueSchema = envelopeValueSchema(schema);
+    const valueType = envelopeValueTypes[name];
+    if (valueSchema && valueType && valueSchema.properties) {
+      lines.push(`type ${name} = Response[${valueType}]`);
+      lines.push('');
+      lines.push(...goStruct(valueType, valueSchema.properties, spec));
+      lines.push('');
+    } else if (valueSchema) {
+      lines.push(`type ${name} = Response[${schemaToGoType(valueSchema, spec)}]`);
       lines.push('');
+    } else if (schema.type === 'object' && schema.properties) {
+      lines.push(...goStruct(name, schema.properties, spec));
+      lines.push('');
+
+      if (acceptsDefaults(schema, spec)) {
+        lines.push(`func (c *${name}) applyDefaults(d callDefaults) {`);
+        for (const field of commitmentFields) {
+          if (isCommitmentField(schema.properties[field])) {
+            const goFieldName = toGoFieldName(field);
+            lines.push(`\tif c.${goFieldName} == "" {`);
+            lines.push(`\t\tc.${goFieldName} = d.commitment`);
+            lines.push('\t}');
+          }
+        }
+        if (schema.properties[minContextSlotField]?.type === 'integer') {
+          const goFieldName = toGoFieldName(minContextSlotField);
+          lines.push(`\tif c.${goFieldName} < d.minContextSlot {`);
+          lines.push(`\t\tc.${goFieldName} = d.minContextSlot`);
+          lines.push('\t}');
+        }
+        lines.push('}');
+        lines.push('');
+      }
     } else if (schema.enum) {
       lines.push(`type ${name} string`);
       lines.push('');
@@ -130,8 +286,10 @@ function generateClient(spec: OpenRpcSpec): string {
   lines.push('\t"context"');
   lines.push('\t"encoding/json"');
   lines.push('\t"fmt"');
+  lines.push('\t"log/slog"');
   lines.push('\t"net/http"');
   lines.push('\t"sync/atomic"');
+  lines.push('\t"time"');
   lines.push(')');
   lines.push('');
 
@@ -154,7 +312,7 @@ function generateClient(spec: OpenRpcSpec): string {
 
   lines.push('type rpcResponse struct {');
   lines.push('\tJsonRPC string          `json:"jsonrpc"`');
-  lines.push('\tID      uint64          `json:"id"`');
+  lines.push('\tID      *uint64         `json:"id"`');
   lines.push('\tResult  json.RawMessage `json:"result"`');
   lines.push('\tError   *RPCError       `json:"error,omitempty"`');
   lines.push('}');
@@ -172,61 +330,174 @@ function generateClient(spec: OpenRpcSpec): string {
   lines.push('}');
   lines.push('');
 
+  lines.push('// validator is implemented by params that can be checked locally before a');
+  lines.push('// request goes out.');
+  lines.push('type validator interface {');
+  lines.push('\tValidate() error');
+  lines.push('}');
+  lines.push('');
+
   // Client struct
   lines.push('type Client struct {');
   lines.push('\tendpoint  string');
   lines.push('\thttpClient *http.Client');
   lines.push('\trequestID  uint64');
+  lines.push('');
+  lines.push('\tdefaultCommitment Commitment');
+  lines.push('\tsession           *Session');
+  lines.push('\tinterceptors      []Interceptor');
+  lines.push('\tinvoker           Invoker');
+  lines.push('\tlogger            *slog.Logger');
+  lines.push('\tlogBodyLimit      int');
+  lines.push('\tmaxResponseBytes  int64');
+  lines.push('\tbodyReadTimeout   time.Duration');
+  lines.push('\tcompression       *compression');
+  lines.push('\tlimiter           *limiter');
+  lines.push('\tdedup             *dedup');
+  lines.push('\tcache             *responseCache');
   lines.push('}');
   lines.push('');
 
   // NewClient
-  lines.push('func NewClient(endpoint string) *Client {');
-  lines.push('\treturn &Client{');
-  lines.push('\t\tendpoint:   endpoint,');
-  lines.push('\t\thttpClient: http.DefaultClient,');
+  lines.push('func NewClient(endpoint string, opts ...ClientOption) *Client {');
+  lines.push('\tc := &Client{');
+  lines.push('\t\tendpoint:    endpoint,');
+  lines.push('\t\thttpClient:  http.DefaultClient,');
+  lines.push('\t\tcompression: newCompression(),');
+  lines.push('\t}');
+  lines.push('\tfor _, opt := range opts {');
+  lines.push('\t\topt(c)');
   lines.push('\t}');
+  lines.push('\tc.invoker = chainInterceptors(c.interceptors, c.transport())');
+  lines.push('\treturn c');
   lines.push('}');
   lines.push('');
 
-  // Generic call method
-  lines.push('func (c *Client) call(ctx context.Context, method string, params []interface{}, result interface{}) error {');
-  lines.push('\tid := atomic.AddUint64(&c.requestID, 1)');
+  // Request construction, shared by send and the streaming helpers
+  lines.push('func (c *Client) newRequest(method string, params []interface{}) *Request {');
+  lines.push('\tif params == nil {');
+  lines.push('\t\tparams = []interface{}{}');
+  lines.push('\t}');
+  lines.push('');
+  lines.push('\treturn &Request{');
+  lines.push('\t\tID:       atomic.AddUint64(&c.requestID, 1),');
+  lines.push('\t\tEndpoint: c.endpoint,');
+  lines.push('\t\tMethod:   method,');
+  lines.push('\t\tParams:   params,');
+  lines.push('\t\tHeader:   make(http.Header),');
+  lines.push('\t}');
+  lines.push('}');
+  lines.push('');
+
+  lines.push('func (c *Client) newHTTPRequest(ctx context.Context, r *Request) (*http.Request, error) {');
+  lines.push('\tfor _, p := range r.Params {');
+  lines.push('\t\tif v, ok := p.(validator); ok {');
+  lines.push('\t\t\tif err := v.Validate(); err != nil {');
+  lines.push('\t\t\t\treturn nil, fmt.Errorf("invalid %s params: %w", r.Method, err)');
+  lines.push('\t\t\t}');
+  lines.push('\t\t}');
+  lines.push('\t}');
   lines.push('');
   lines.push('\treqBody := rpcRequest{');
   lines.push('\t\tJsonRPC: "2.0",');
-  lines.push('\t\tID:      id,');
-  lines.push('\t\tMethod:  method,');
-  lines.push('\t\tParams:  params,');
+  lines.push('\t\tID:      r.ID,');
+  lines.push('\t\tMethod:  r.Method,');
+  lines.push('\t\tParams:  r.Params,');
   lines.push('\t}');
   lines.push('');
   lines.push('\tbody, err := json.Marshal(reqBody)');
   lines.push('\tif err != nil {');
-  lines.push('\t\treturn fmt.Errorf("marshal request: %w", err)');
+  lines.push('\t\treturn nil, fmt.Errorf("marshal request: %w", err)');
   lines.push('\t}');
   lines.push('');
-  lines.push('\treq, err := http.NewRequestWithContext(ctx, "POST", c.endpoint, bytes.NewReader(body))');
+  lines.push('\treq, err := http.NewRequestWithContext(ctx, "POST", r.Endpoint, bytes.NewReader(body))');
   lines.push('\tif err != nil {');
-  lines.push('\t\treturn fmt.Errorf("create request: %w", err)');
+  lines.push('\t\treturn nil, fmt.Errorf("create request: %w", err)');
+  lines.push('\t}');
+  lines.push('\tfor k, v := range r.Header {');
+  lines.push('\t\treq.Header[k] = v');
   lines.push('\t}');
   lines.push('\treq.Header.Set("Content-Type", "application/json")');
+  lines.push('\tc.compression.setAccept(req)');
+  lines.push('\treturn req, nil');
+  lines.push('}');
+  lines.push('');
+
+  // HTTP transport, the innermost Invoker of the interceptor chain
+  lines.push('func (c *Client) roundTrip(ctx context.Context, r *Request) (*RawResponse, error) {');
+  lines.push('\tctx, cancel := context.WithCancel(ctx)');
+  lines.push('\tdefer cancel()');
+  lines.push('');
+  lines.push('\treq, err := c.newHTTPRequest(ctx, r)');
+  lines.push('\tif err != nil {');
+  lines.push('\t\treturn nil, err');
+  lines.push('\t}');
   lines.push('');
   lines.push('\tresp, err := c.httpClient.Do(req)');
   lines.push('\tif err != nil {');
-  lines.push('\t\treturn fmt.Errorf("do request: %w", err)');
+  lines.push('\t\treturn nil, fmt.Errorf("do request: %w", redactURLError(err))');
   lines.push('\t}');
   lines.push('\tdefer resp.Body.Close()');
   lines.push('');
+  lines.push('\tbody, err := c.readBody(ctx, r, resp, cancel)');
+  lines.push('\tif err != nil {');
+  lines.push('\t\treturn nil, err');
+  lines.push('\t}');
+  lines.push('');
   lines.push('\tvar rpcResp rpcResponse');
-  lines.push('\tif err := json.NewDecoder(resp.Body).Decode(&rpcResp); err != nil {');
-  lines.push('\t\treturn fmt.Errorf("decode response: %w", err)');
+  lines.push('\tif err := json.Unmarshal(body, &rpcResp); err != nil {');
+  lines.push('\t\tif err := checkStatus(r, resp.StatusCode, body, nil); err != nil {');
+  lines.push('\t\t\treturn nil, err');
+  lines.push('\t\t}');
+  lines.push('\t\treturn nil, fmt.Errorf("decode response: %w", err)');
+  lines.push('\t}');
+  lines.push('\tif err := checkStatus(r, resp.StatusCode, body, &rpcResp); err != nil {');
+  lines.push('\t\treturn nil, err');
+  lines.push('\t}');
+  lines.push('\tif err := checkResponse(r, &rpcResp); err != nil {');
+  lines.push('\t\treturn nil, err');
+  lines.push('\t}');
+  lines.push('');
+  lines.push('\treturn &RawResponse{');
+  lines.push('\t\tID:         r.ID,');
+  lines.push('\t\tResult:     rpcResp.Result,');
+  lines.push('\t\tError:      rpcResp.Error,');
+  lines.push('\t\tStatusCode: resp.StatusCode,');
+  lines.push('\t\tHeader:     resp.Header,');
+  lines.push('\t\tBodySize:   int64(len(body)),');
+  lines.push('\t}, nil');
+  lines.push('}');
+  lines.push('');
+
+  // Single call through the interceptor chain, returning the raw result
+  lines.push('func (c *Client) send(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {');
+  lines.push('\tresp, err := c.invoker(ctx, c.newRequest(method, params))');
+  lines.push('\tif err != nil {');
+  lines.push('\t\treturn nil, err');
+  lines.push('\t}');
+  lines.push('');
+  lines.push('\tif resp.Error != nil {');
+  lines.push('\t\treturn nil, resp.Error');
   lines.push('\t}');
   lines.push('');
-  lines.push('\tif rpcResp.Error != nil {');
-  lines.push('\t\treturn rpcResp.Error');
+  lines.push('\treturn resp.Result, nil');
+  lines.push('}');
+  lines.push('');
+
+  // Generic call method
+  lines.push('func (c *Client) call(ctx context.Context, method string, params []interface{}, result interface{}) error {');
+  lines.push('\tvar raw json.RawMessage');
+  lines.push('\tvar err error');
+  lines.push('\tif c.session != nil {');
+  lines.push('\t\traw, err = c.session.send(ctx, method, params)');
+  lines.push('\t} else {');
+  lines.push('\t\traw, err = c.send(ctx, method, params)');
+  lines.push('\t}');
+  lines.push('\tif err != nil {');
+  lines.push('\t\treturn err');
   lines.push('\t}');
   lines.push('');
-  lines.push('\tif err := json.Unmarshal(rpcResp.Result, result); err != nil {');
+  lines.push('\tif err := json.Unmarshal(raw, result); err != nil {');
   lines.push('\t\treturn fmt.Errorf("unmarshal result: %w", err)');
   lines.push('\t}');
   lines.push('');
@@ -234,48 +505,46 @@ function generateClient(spec: OpenRpcSpec): string {
   lines.push('}');
   lines.push('');
 
-  // Generate methods
-  for (const method of spec.methods) {
-    const methodName = toGoName(method.name);
-    const params = method.params;
-
-    // Build parameter list
-    const goParams = ['ctx context.Context'];
-    for (const p of params) {
-      const paramName = p.name;
-      let goType = schemaToGoType(p.schema, spec);
-      if (!p.required) {
-        goType = '*' + goType; // Pointer for optional
-      }
-      goParams.push(`${paramName} ${goType}`);
-    }
+  lines.push('// Call invokes any RPC method and decodes its result into result. It covers');
+  lines.push('// provider-specific methods and ones the spec does not describe yet.');
+  lines.push('func (c *Client) Call(ctx context.Context, method string, params []interface{}, result interface{}) error {');
+  lines.push('\treturn c.call(ctx, method, params, result)');
+  lines.push('}');
+  lines.push('');
 
-    // Return type
-    let returnType = schemaToGoType(method.result.schema, spec);
-    if (returnType === 'interface{}') {
-      returnType = 'json.RawMessage';
-    }
+  lines.push('// CallRaw invokes any RPC method and returns its result undecoded.');
+  lines.push('func (c *Client) CallRaw(ctx context.Context, method string, params []interface{}) (json.RawMessage, error) {');
+  lines.push('\tvar result json.RawMessage');
+  lines.push('\terr := c.call(ctx, method, params, &result)');
+  lines.push('\treturn result, err');
+  lines.push('}');
+  lines.push('');
 
+  // Generate methods
+  for (const m of spec.methods.map((method) => toGoMethod(method, spec))) {
     // Generate doc comment
-    lines.push(`// ${methodName} ${method.summary}`);
+    lines.push(`// ${m.name} ${m.summary}`);
 
     // Generate method signature
-    lines.push(`func (c *Client) ${methodName}(${goParams.join(', ')}) (${returnType}, error) {`);
+    lines.push(`func (c *Client) ${m.name}(${goParamList(m)}) (${m.returnType}, error) {`);
     lines.push('\tparams := make([]interface{}, 0)');
 
-    for (const p of params) {
-      if (p.required) {
-        lines.push(`\tparams = append(params, ${p.name})`);
+    for (const { name, param } of m.params) {
+      if (param.required) {
+        lines.push(`\tparams = append(params, ${name})`);
       } else {
-        lines.push(`\tif ${p.name} != nil {`);
-        lines.push(`\t\tparams = append(params, *${p.name})`);
+        if (acceptsDefaults(param.schema, spec)) {
+          lines.push(`\t${name} = withDefaults(c.defaults(ctx, "${m.rpcName}"), ${name})`);
+        }
+        lines.push(`\tif ${name} != nil {`);
+        lines.push(`\t\tparams = append(params, *${name})`);
         lines.push('\t}');
       }
     }
 
     lines.push('');
-    lines.push(`\tvar result ${returnType}`);
-    lines.push(`\terr := c.call(ctx, "${method.name}", params, &result)`);
+    lines.push(`\tvar result ${m.returnType}`);
+    lines.push(`\terr := c.call(ctx, "${m.rpcName}", params, &result)`);
     lines.push('\treturn result, err');
     lines.push('}');
     lines.push('');
@@ -284,6 +553,147 @@ function generateClient(spec: OpenRpcSpec): string {
   return lines.join('\n');
 }
 
+// Signatures of the raw-call escape hatches, which the spec does not list.
+const rawCallMethods = [
+  {
+    name: 'Call',
+    params: 'ctx context.Context, method string, params []interface{}, result interface{}',
+    args: ['method', 'params', 'result'],
+    results: 'error',
+  },
+  {
+    name: 'CallRaw',
+    params: 'ctx context.Context, method string, params []interface{}',
+    args: ['method', 'params'],
+    results: '(json.RawMessage, error)',
+  },
+];
+
+function generateInterface(spec: OpenRpcSpec): string {
+  const lines: string[] = [];
+
+  lines.push('package solana');
+  lines.push('');
+  lines.push('import (');
+  lines.push('\t"context"');
+  lines.push('\t"encoding/json"');
+  lines.push(')');
+  lines.push('');
+  lines.push('// RPC is the set of methods implemented by Client. Depend on it instead of');
+  lines.push('// *Client to substitute a MockRPC in tests or to wrap a client with extra');
+  lines.push('// behaviour.');
+  lines.push('type RPC interface {');
+  for (const raw of rawCallMethods) {
+    lines.push(`\t${raw.name}(${raw.params}) ${raw.results}`);
+  }
+  for (const m of spec.methods.map((method) => toGoMethod(method, spec))) {
+    lines.push(`\t${m.name}(${goParamList(m)}) (${m.returnType}, error)`);
+  }
+  lines.push('}');
+  lines.push('');
+  lines.push('var _ RPC = (*Client)(nil)');
+  lines.push('');
+
+  return lines.join('\n');
+}
+
+function generateMock(spec: OpenRpcSpec): string {
+  const lines: string[] = [];
+  const methods = spec.methods.map((method) => toGoMethod(method, spec));
+
+  lines.push('package solana');
+  lines.push('');
+  lines.push('import (');
+  lines.push('\t"context"');
+  lines.push('\t"encoding/json"');
+  lines.push('\t"errors"');
+  lines.push('\t"fmt"');
+  lines.push('\t"sync"');
+  lines.push(')');
+  lines.push('');
+  lines.push('// ErrNotMocked is returned by MockRPC methods whose function field is unset.');
+  lines.push('var ErrNotMocked = errors.New("method not mocked")');
+  lines.push('');
+  lines.push('// MockCall records a single call made on a MockRPC.');
+  lines.push('type MockCall struct {');
+  lines.push('\tMethod string');
+  lines.push('\tArgs   []interface{}');
+  lines.push('}');
+  lines.push('');
+  lines.push('// MockRPC implements RPC without a network. Each method calls the matching');
+  lines.push('// function field, or returns ErrNotMocked when it is nil, and every call is');
+  lines.push('// recorded for later inspection with Calls.');
+  lines.push('type MockRPC struct {');
+  for (const raw of rawCallMethods) {
+    lines.push(`\t${raw.name}Func func(${raw.params}) ${raw.results}`);
+  }
+  for (const m of methods) {
+    lines.push(`\t${m.name}Func func(${goParamList(m)}) (${m.returnType}, error)`);
+  }
+  lines.push('');
+  lines.push('\tmu    sync.Mutex');
+  lines.push('\tcalls []MockCall');
+  lines.push('}');
+  lines.push('');
+  lines.push('var _ RPC = (*MockRPC)(nil)');
+  lines.push('');
+  lines.push('// Calls returns the calls made so far, in order. The context argument is omitted.');
+  lines.push('func (m *MockRPC) Calls() []MockCall {');
+  lines.push('\tm.mu.Lock()');
+  lines.push('\tdefer m.mu.Unlock()');
+  lines.push('\treturn append([]MockCall(nil), m.calls...)');
+  lines.push('}');
+  lines.push('');
+  lines.push('// CallsTo returns the recorded calls to the named method, such as "GetBalance".');
+  lines.push('func (m *MockRPC) CallsTo(method string) []MockCall {');
+  lines.push('\tvar out []MockCall');
+  lines.push('\tfor _, call := range m.Calls() {');
+  lines.push('\t\tif call.Method == method {');
+  lines.push('\t\t\tout = append(out, call)');
+  lines.push('\t\t}');
+  lines.push('\t}');
+  lines.push('\treturn out');
+  lines.push('}');
+  lines.push('');
+  lines.push('func (m *MockRPC) record(method string, args ...interface{}) {');
+  lines.push('\tm.mu.Lock()');
+  lines.push('\tdefer m.mu.Unlock()');
+  lines.push('\tm.calls = append(m.calls, MockCall{Method: method, Args: args})');
+  lines.push('}');
+  lines.push('');
+
+  for (const raw of rawCallMethods) {
+    const args = raw.args.join(', ');
+    lines.push(`func (m *MockRPC) ${raw.name}(${raw.params}) ${raw.results} {`);
+    lines.push(`\tm.record("${raw.name}", ${args})`);
+    lines.push(`\tif m.${raw.name}Func == nil {`);
+    if (raw.results === 'error') {
+      lines.push(`\t\treturn fmt.Errorf("${raw.name}: %w", ErrNotMocked)`);
+    } else {
+      lines.push(`\t\treturn nil, fmt.Errorf("${raw.name}: %w", ErrNotMocked)`);
+    }
+    lines.push('\t}');
+    lines.push(`\treturn m.${raw.name}Func(ctx, ${args})`);
+    lines.push('}');
+    lines.push('');
+  }
+
+  for (const m of methods) {
+    const args = m.params.map((p) => p.name);
+    lines.push(`func (m *MockRPC) ${m.name}(${goParamList(m)}) (${m.returnType}, error) {`);
+    lines.push(`\tm.record(${[`"${m.name}"`, ...args].join(', ')})`);
+    lines.push(`\tif m.${m.name}Func == nil {`);
+    lines.push(`\t\tvar zero ${m.returnType}`);
+    lines.push(`\t\treturn zero, fmt.Errorf("${m.name}: %w", ErrNotMocked)`);
+    lines.push('\t}');
+    lines.push(`\treturn m.${m.name}Func(${['ctx', ...args].join(', ')})`);
+    lines.push('}');
+    lines.push('');
+  }
+
+  return lines.join('\n');
+}
+
 async function main() {
   const specPath = path.join(__dirname, '..', 'spec', 'solana-rpc.openrpc.json');
   const outDir = path.join(__dirname, '..', 'generated', 'go');
@@ -306,6 +716,12 @@ async function main() {
   console.log('Generating client.go...');
   fs.writeFileSync(path.join(outDir, 'client.go'), generateClient(spec));
 
+  // Generate interface and mock
+  console.log('Generating rpc.go...');
+  fs.writeFileSync(path.join(outDir, 'rpc.go'), generateInterface(spec));
+  console.log('Generating mock.go...');
+  fs.writeFileSync(path.join(outDir, 'mock.go'), generateMock(spec));
+
   // Generate go.mod
   const goMod = `module github.com/solana-rpc/client
 
@@ -0,0 +1,411 @@
+package solana_test
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"math/big"
+	"reflect"
+	"strings"
+	"testing"
+
+	solana "github.com/solana-rpc/client"
+	"github.com/solana-rpc/client/anchor"
+	"github.com/solana-rpc/client/borsh"
+)
+
+const legacyIDL = `{
+  "version": "0.1.0",
+  "name": "legacy_counter",
+  "instructions": [{
+    "name": "initializeV2",
+    "accounts": [
+      {"name": "counter", "isMut": true, "isSigner": false},
+      {"name": "group", "accounts": [{"name": "owner", "isMut": false, "isSigner": true}]}
+    ],
+    "args": [{"name": "start", "type": "u32"}]
+  }],
+  "accounts": [{
+    "name": "Counter",
+    "type": {"kind": "struct", "fields": [
+      {"name": "owner", "type": "publicKey"},
+      {"name": "count", "type": "u64"},
+      {"name": "history", "type": {"vec": {"defined": "Entry"}}},
+      {"name": "tag", "type": {"array": ["u8", 4]}}
+    ]}
+  }],
+  "types": [{"name": "Entry", "type": {"kind": "struct", "fields": [{"name": "delta", "type": "i64"}]}}],
+  "events": [{"name": "Initialized", "fields": [{"name": "start", "type": "u32", "index": false}]}],
+  "errors": [{"code": 6000, "name": "Overflow", "msg": "counter overflowed"}],
+  "metadata": {"address": "PROGRAM"}
+}`
+
+const currentIDL = `{
+  "address": "PROGRAM",
+  "metadata": {"name": "counter", "version": "0.1.0", "spec": "0.1.0"},
+  "instructions": [{
+    "name": "increment",
+    "discriminator": [11, 18, 104, 9, 104, 174, 59, 33],
+    "accounts": [
+      {"name": "counter", "writable": true},
+      {"name": "authority", "signer": true},
+      {"name": "extra", "optional": true}
+    ],
+    "args": [{"name": "amount", "type": "u64"}, {"name": "memo", "type": {"option": "string"}}]
+  }],
+  "accounts": [{"name": "Counter", "discriminator": [255, 176, 4, 245, 188, 253, 124, 25]}],
+  "events": [{"name": "Incremented", "discriminator": [1, 2, 3, 4, 5, 6, 7, 8]}],
+  "types": [
+    {"name": "Counter", "type": {"kind": "struct", "fields": [
+      {"name": "authority", "type": "pubkey"},
+      {"name": "mode", "type": {"defined": {"name": "Mode"}}}
+    ]}},
+    {"name": "Mode", "type": {"kind": "enum", "variants": [
+      {"name": "Off"},
+      {"name": "Limit", "fields": [{"name": "max", "type": "u64"}]},
+      {"name": "Pair", "fields": ["u8", "i16"]}
+    ]}},
+    {"name": "Incremented", "type": {"kind": "struct", "fields": [
+      {"name": "count", "type": "u64"},
+      {"name": "total", "type": "i128"}
+    ]}}
+  ]
+}`
+
+func parseTestIDL(t *testing.T, doc string, programID solana.Pubkey) *anchor.IDL {
+	t.Helper()
+	idl, err := anchor.ParseIDL([]byte(strings.ReplaceAll(doc, "PROGRAM", programID)))
+	if err != nil {
+		t.Fatalf("ParseIDL failed: %v", err)
+	}
+	return idl
+}
+
+func sighash(preimage string) []byte {
+	sum := sha256.Sum256([]byte(preimage))
+	return sum[:8]
+}
+
+func TestAnchorLegacyIDL(t *testing.T) {
+	programID := testKey(9)
+	idl := parseTestIDL(t, legacyIDL, programID)
+
+	if idl.Address != programID || idl.Name != "legacy_counter" {
+		t.Fatalf("unexpected IDL header %q %q", idl.Address, idl.Name)
+	}
+	if got := idl.Instructions[0].Discriminator; !reflect.DeepEqual(got, sighash("global:initialize_v2")) {
+		t.Fatalf("unexpected instruction discriminator %v", got)
+	}
+	if got := idl.Accounts[0].Discriminator; !reflect.DeepEqual(got, sighash("account:Counter")) {
+		t.Fatalf("unexpected account discriminator %v", got)
+	}
+	if got := idl.Events[0].Discriminator; !reflect.DeepEqual(got, sighash("event:Initialized")) {
+		t.Fatalf("unexpected event discriminator %v", got)
+	}
+	if group := idl.Instructions[0].Accounts[1]; len(group.Accounts) != 1 || !group.Accounts[0].Signer {
+		t.Fatalf("unexpected account group %+v", group)
+	}
+
+	e := borsh.NewEncoder()
+	e.Write(sighash("account:Counter"))
+	e.Pubkey(testKey(4))
+	e.U64(7)
+	e.Len(1)
+	e.I64(-3)
+	e.Write([]byte{1, 2, 3, 4})
+	e.Write(make([]byte, 16)) // spare room in the account
+	data := e.Bytes()
+
+	coder := anchor.NewCoder(idl)
+	account, err := coder.DecodeAccountInfo(&solana.AccountInfo{
+		Owner: programID,
+		Data:  solana.AccountData{Bytes: data, Encoding: solana.EncodingBase64},
+	})
+	if err != nil {
+		t.Fatalf("DecodeAccountInfo failed: %v", err)
+	}
+	want := map[string]any{
+		"owner":   testKey(4),
+		"count":   uint64(7),
+		"history": []any{map[string]any{"delta": int64(-3)}},
+		"tag":     []byte{1, 2, 3, 4},
+	}
+	if account.Name != "Counter" || !reflect.DeepEqual(account.Value, want) {
+		t.Fatalf("unexpected account %s %#v", account.Name, account.Value)
+	}
+
+	type entry struct{ Delta int64 }
+	type counter struct {
+		Owner   solana.Pubkey `borsh:"pubkey"`
+		Count   uint64
+		History []entry
+		Tag     [4]byte
+	}
+	if err := coder.RegisterAccount("Counter", counter{}); err != nil {
+		t.Fatalf("RegisterAccount failed: %v", err)
+	}
+	account, err = coder.DecodeAccount(data)
+	if err != nil {
+		t.Fatalf("DecodeAccount failed: %v", err)
+	}
+	wantStruct := &counter{Owner: testKey(4), Count: 7, History: []entry{{-3}}, Tag: [4]byte{1, 2, 3, 4}}
+	if !reflect.DeepEqual(account.Value, wantStruct) {
+		t.Fatalf("unexpected registered account %#v", account.Value)
+	}
+
+	if _, err := coder.DecodeAccountInfo(&solana.AccountInfo{Owner: testKey(5), Data: solana.AccountData{Bytes: data, Encoding: solana.EncodingBase64}}); err == nil {
+		t.Fatal("expected an error for an account owned by another program")
+	}
+	if _, err := coder.DecodeAccount([]byte("not an account")); !errors.Is(err, anchor.ErrUnknownDiscriminator) {
+		t.Fatalf("expected ErrUnknownDiscriminator, got %v", err)
+	}
+	if err := coder.RegisterAccount("Missing", counter{}); err == nil {
+		t.Fatal("expected an error registering an unknown account")
+	}
+
+	failure := &solana.TransactionError{Kind: "InstructionError", Instruction: &solana.InstructionError{Kind: "Custom", Custom: 6000}}
+	if e, ok := coder.TransactionError(failure); !ok || e.Name != "Overflow" {
+		t.Fatalf("unexpected program error %+v %v", e, ok)
+	}
+}
+
+func TestAnchorDecodeTransactionAndEvents(t *testing.T) {
+	programID := testKey(9)
+	coder := anchor.NewCoder(parseTestIDL(t, currentIDL, programID))
+
+	ixData := borsh.NewEncoder()
+	ixData.Write([]byte{11, 18, 104, 9, 104, 174, 59, 33})
+	ixData.U64(5)
+	ixData.Option(true)
+	ixData.String("hi")
+
+	msg := solana.Message{
+		Header:          solana.MessageHeader{NumRequiredSignatures: 1, NumReadonlyUnsignedAccounts: 2},
+		AccountKeys:     []solana.Pubkey{testKey(1), testKey(2), programID, testKey(3)},
+		RecentBlockhash: testKey(8),
+		Instructions: []solana.CompiledInstruction{
+			{ProgramIDIndex: 3, Data: []byte{1}},
+			// The optional account is omitted by passing the program ID,
+			// and one remaining account follows.
+			{ProgramIDIndex: 2, Accounts: []uint8{1, 0, 2, 3}, Data: ixData.Bytes()},
+		},
+	}
+	wire, err := (&solana.Transaction{Signatures: []solana.Signature{solana.EncodeBase58(make([]byte, 64))}, Message: msg}).MarshalBinary()
+	if err != nil {
+		t.Fatalf("MarshalBinary failed: %v", err)
+	}
+
+	event := borsh.NewEncoder()
+	event.Write([]byte{1, 2, 3, 4, 5, 6, 7, 8})
+	event.U64(6)
+	event.I128(borsh.Int128{Lo: ^uint64(1), Hi: ^uint64(0)}) // -2
+	logs := []string{
+		"Program " + programID + " invoke [1]",
+		"Program log: Instruction: Increment",
+		"Program data: " + base64.StdEncoding.EncodeToString(event.Bytes()),
+		"Program data: aGk=",
+		"Program " + programID + " success",
+	}
+
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		return map[string]interface{}{
+			"slot":        1,
+			"transaction": []string{base64.StdEncoding.EncodeToString(wire), "base64"},
+			"meta": map[string]interface{}{
+				"err":             nil,
+				"logMessages":     logs,
+				"loadedAddresses": map[string]interface{}{"writable": []string{}, "readonly": []string{}},
+			},
+			"version": "legacy",
+		}, nil
+	})
+	client := solana.NewClient(server.URL)
+
+	resp, err := client.GetTransaction(context.Background(), "sig", &solana.GetTransactionConfig{Encoding: solana.TransactionEncodingBase64})
+	if err != nil {
+		t.Fatalf("GetTransaction failed: %v", err)
+	}
+	instructions, err := coder.DecodeTransaction(&resp)
+	if err != nil {
+		t.Fatalf("DecodeTransaction failed: %v", err)
+	}
+	if len(instructions) != 1 {
+		t.Fatalf("expected one instruction, got %+v", instructions)
+	}
+	ix := instructions[0]
+	wantArgs := map[string]any{"amount": uint64(5), "memo": "hi"}
+	wantAccounts := map[string]solana.Pubkey{"counter": testKey(2), "authority": testKey(1)}
+	if ix.Name != "increment" || ix.Index != 1 || !reflect.DeepEqual(ix.Args, wantArgs) ||
+		!reflect.DeepEqual(ix.Accounts, wantAccounts) || !reflect.DeepEqual(ix.Remaining, []solana.Pubkey{testKey(3)}) {
+		t.Fatalf("unexpected instruction %+v", ix)
+	}
+
+	events, err := coder.DecodeEvents(resp.Meta.LogMessages)
+	if err != nil {
+		t.Fatalf("DecodeEvents failed: %v", err)
+	}
+	if len(events) != 1 || events[0].Name != "Incremented" {
+		t.Fatalf("unexpected events %+v", events)
+	}
+	fields := events[0].Value.(map[string]any)
+	if fields["count"] != uint64(6) || fields["total"].(*big.Int).Cmp(big.NewInt(-2)) != 0 {
+		t.Fatalf("unexpected event fields %v", fields)
+	}
+}
+
+func TestAnchorDecodeEnums(t *testing.T) {
+	programID := testKey(9)
+	coder := anchor.NewCoder(parseTestIDL(t, currentIDL, programID))
+	discriminator := []byte{255, 176, 4, 245, 188, 253, 124, 25}
+
+	tests := []struct {
+		variant func(e *borsh.Encoder)
+		want    any
+	}{
+		{func(e *borsh.Encoder) { e.U8(0) }, "Off"},
+		{func(e *borsh.Encoder) { e.U8(1); e.U64(10) }, map[string]any{"Limit": map[string]any{"max": uint64(10)}}},
+		{func(e *borsh.Encoder) { e.U8(2); e.U8(1); e.I16(-1) }, map[string]any{"Pair": []any{uint8(1), int16(-1)}}},
+	}
+	for _, tt := range tests {
+		e := borsh.NewEncoder()
+		e.Write(discriminator)
+		e.Pubkey(testKey(1))
+		tt.variant(e)
+		account, err := coder.DecodeAccount(e.Bytes())
+		if err != nil {
+			t.Fatalf("DecodeAccount failed: %v", err)
+		}
+		if got := account.Value.(map[string]any)["mode"]; !reflect.DeepEqual(got, tt.want) {
+			t.Errorf("got mode %#v, want %#v", got, tt.want)
+		}
+	}
+
+	e := borsh.NewEncoder()
+	e.Write(discriminator)
+	e.Pubkey(testKey(1))
+	e.U8(7)
+	if _, err := coder.DecodeAccount(e.Bytes()); err == nil {
+		t.Fatal("expected an error for an unknown variant")
+	}
+}
+
+func TestAnchorSnakeCase(t *testing.T) {
+	for in, want := range map[string]string{
+		"initialize":    "initialize",
+		"initializeV2":  "initialize_v2",
+		"setMaxSupply":  "set_max_supply",
+		"updateNFTMeta": "update_nft_meta",
+		"already_snake": "already_snake",
+	} {
+		if got := anchor.SnakeCase(in); got != want {
+			t.Errorf("SnakeCase(%q) = %q, want %q", in, got, want)
+		}
+	}
+}
+
+func TestBorshRoundTrip(t *testing.T) {
+	type inner struct {
+		Flag bool
+		Note *string
+	}
+	type record struct {
+		Owner   solana.Pubkey `borsh:"pubkey"`
+		Small   int8
+		Amount  uint64
+		Ratio   float64
+		Name    string
+		Raw     []byte
+		Items   []inner
+		Fixed   [2]uint16
+		Big     borsh.Uint128
+		skipped int
+	}
+	note := "memo"
+	in := record{
+		Owner:  testKey(7),
+		Small:  -5,
+		Amount: 1 << 40,
+		Ratio:  0.25,
+		Name:   "counter",
+		Raw:    []byte{9, 8},
+		Items:  []inner{{Flag: true, Note: &note}, {}},
+		Fixed:  [2]uint16{1, 65535},
+		Big:    borsh.Uint128{Lo: 1, Hi: 1},
+	}
+	data, err := borsh.Marshal(in)
+	if err != nil {
+		t.Fatalf("Marshal failed: %v", err)
+	}
+	if len(data) != 32+1+8+8+(4+7)+(4+2)+(4+1+1+4+4+1+1)+4+16 {
+		t.Fatalf("unexpected encoded length %d", len(data))
+	}
+
+	var out record
+	if err := borsh.Unmarshal(data, &out); err != nil {
+		t.Fatalf("Unmarshal failed: %v", err)
+	}
+	if !reflect.DeepEqual(out, in) {
+		t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", out, in)
+	}
+	if got := out.Big.String(); got != "18446744073709551617" {
+		t.Fatalf("unexpected u128 %s", got)
+	}
+
+	if err := borsh.Unmarshal(data[:len(data)-1], &out); !errors.Is(err, borsh.ErrUnexpectedEOF) {
+		t.Fatalf("expected ErrUnexpectedEOF, got %v", err)
+	}
+	if err := borsh.Unmarshal(append(data, 0), &out); err == nil {
+		t.Fatal("expected an error for trailing bytes")
+	}
+}
+
+// borshKind encodes itself with pointer-receiver methods, like the types
+// anchor-gen writes.
+type borshKind struct{ N uint8 }
+
+func (k *borshKind) MarshalBorsh(e *borsh.Encoder) error {
+	e.U8(k.N)
+	return nil
+}
+
+func (k *borshKind) UnmarshalBorsh(d *borsh.Decoder) (err error) {
+	k.N, err = d.U8()
+	return err
+}
+
+func TestBorshOptionalMarshaler(t *testing.T) {
+	type holder struct {
+		K *borshKind
+		V borshKind
+	}
+	for _, tc := range []struct {
+		in   holder
+		want []byte
+	}{
+		{holder{K: &borshKind{7}, V: borshKind{3}}, []byte{1, 7, 3}},
+		{holder{V: borshKind{3}}, []byte{0, 3}},
+	} {
+		data, err := borsh.Marshal(tc.in)
+		if err != nil {
+			t.Fatalf("Marshal failed: %v", err)
+		}
+		if !bytes.Equal(data, tc.want) {
+			t.Fatalf("Marshal(%+v) = %v, want %v", tc.in, data, tc.want)
+		}
+		var out holder
+		if err := borsh.Unmarshal(data, &out); err != nil {
+			t.Fatalf("Unmarshal failed: %v", err)
+		}
+		if !reflect.DeepEqual(out, tc.in) {
+			t.Fatalf("round trip mismatch: got %+v, want %+v", out, tc.in)
+		}
+	}
+
+	// A pointer passed to Marshal is followed, not encoded as an option.
+	data, err := borsh.Marshal(&borshKind{7})
+	if err != nil || !bytes.Equal(data, []byte{7}) {
+		t.Fatalf("Marshal(&borshKind{7}) = %v, %v", data, err)
+	}
+}
@@ -0,0 +1,209 @@
+package solana_test
+
+import (
+	"go/ast"
+	"go/parser"
+	"go/token"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"testing"
+
+	"github.com/solana-rpc/client/anchor/anchorgen"
+)
+
+func TestAnchorGenDeclarations(t *testing.T) {
+	src, err := anchorgen.Generate(parseTestIDL(t, currentIDL, testKey(9)), "")
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+	file, err := parser.ParseFile(token.NewFileSet(), "counter.go", src, 0)
+	if err != nil {
+		t.Fatalf("generated source does not parse: %v", err)
+	}
+	if file.Name.Name != "counter" {
+		t.Fatalf("unexpected package %s", file.Name.Name)
+	}
+
+	declared := map[string]bool{}
+	for _, decl := range file.Decls {
+		switch d := decl.(type) {
+		case *ast.FuncDecl:
+			name := d.Name.Name
+			if d.Recv != nil {
+				recv := d.Recv.List[0].Type
+				if star, ok := recv.(*ast.StarExpr); ok {
+					recv = star.X
+				}
+				name = recv.(*ast.Ident).Name + "." + name
+			}
+			declared[name] = true
+		case *ast.GenDecl:
+			for _, spec := range d.Specs {
+				switch s := spec.(type) {
+				case *ast.TypeSpec:
+					declared[s.Name.Name] = true
+				case *ast.ValueSpec:
+					for _, n := range s.Names {
+						declared[n.Name] = true
+					}
+				}
+			}
+		}
+	}
+	for _, name := range []string{
+		"ProgramID",
+		"Counter", "Counter.MarshalBorsh", "Counter.UnmarshalBorsh", "Counter.MarshalAccount", "Counter.UnmarshalAccount",
+		"CounterAccountDiscriminator", "FetchCounter", "FetchCounterAccounts",
+		"Mode", "ModeKind", "ModeKindOff", "ModeLimit", "ModePair",
+		"IncrementAccounts", "IncrementArgs", "NewIncrementInstruction", "IncrementInstructionDiscriminator",
+		"Incremented", "IncrementedEventDiscriminator", "DecodeEvent", "DecodeEvents",
+	} {
+		if !declared[name] {
+			t.Errorf("generated code does not declare %s", name)
+		}
+	}
+}
+
+// generatedCounterTest exercises the generated package from inside it.
+const generatedCounterTest = `package counter
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	solana "github.com/solana-rpc/client"
+)
+
+func key(b byte) solana.Pubkey {
+	k := make([]byte, 32)
+	k[31] = b
+	return solana.EncodeBase58(k)
+}
+
+func TestGenerated(t *testing.T) {
+	limit := Counter{Authority: key(1), Mode: Mode{Kind: ModeKindLimit, Limit: &ModeLimit{Max: 10}}}
+	data, err := limit.MarshalAccount()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(data) != 8+32+1+8 || !bytes.HasPrefix(data, CounterAccountDiscriminator) {
+		t.Fatalf("unexpected account data %v", data)
+	}
+	var decoded Counter
+	if err := decoded.UnmarshalAccount(append(data, 0, 0)); err != nil {
+		t.Fatal(err)
+	}
+	if decoded.Authority != key(1) || decoded.Mode.Kind != ModeKindLimit || decoded.Mode.Limit.Max != 10 {
+		t.Fatalf("unexpected account %+v", decoded)
+	}
+	if err := decoded.UnmarshalAccount(data[1:]); err == nil {
+		t.Fatal("expected a discriminator error")
+	}
+
+	memo := "hi"
+	ix, err := NewIncrementInstruction(IncrementAccounts{Counter: key(2), Authority: key(1)}, IncrementArgs{Amount: 5, Memo: &memo})
+	if err != nil {
+		t.Fatal(err)
+	}
+	wantData := append(append([]byte(nil), IncrementInstructionDiscriminator...), 5, 0, 0, 0, 0, 0, 0, 0, 1, 2, 0, 0, 0, 'h', 'i')
+	if ix.ProgramID != ProgramID || !bytes.Equal(ix.Data, wantData) {
+		t.Fatalf("unexpected instruction %+v", ix)
+	}
+	wantAccounts := []solana.AccountMeta{
+		{Pubkey: key(2), IsWritable: true},
+		{Pubkey: key(1), IsSigner: true},
+		{Pubkey: ProgramID},
+	}
+	for i, want := range wantAccounts {
+		if ix.Accounts[i] != want {
+			t.Fatalf("account %d: got %+v, want %+v", i, ix.Accounts[i], want)
+		}
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage
+			Method string
+			Params []json.RawMessage
+		}
+		json.NewDecoder(r.Body).Decode(&req)
+		var config struct {
+			Filters []struct {
+				Memcmp struct{ Offset int; Bytes string }
+			}
+		}
+		json.Unmarshal(req.Params[1], &config)
+		if req.Method != "getProgramAccounts" || len(config.Filters) != 2 ||
+			config.Filters[0].Memcmp.Bytes != solana.EncodeBase58(CounterAccountDiscriminator) {
+			t.Errorf("unexpected request %s %s", req.Method, req.Params[1])
+		}
+		json.NewEncoder(w).Encode(map[string]any{"jsonrpc": "2.0", "id": req.ID, "result": []any{
+			map[string]any{"pubkey": key(3), "account": map[string]any{
+				"owner": ProgramID, "data": []string{base64.StdEncoding.EncodeToString(data), "base64"},
+			}},
+		}})
+	}))
+	defer server.Close()
+
+	accounts, err := FetchCounterAccounts(context.Background(), solana.NewClient(server.URL), solana.MemcmpPubkey(8, key(1)))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(accounts) != 1 || accounts[0].Pubkey != key(3) || accounts[0].Account.Mode.Limit.Max != 10 {
+		t.Fatalf("unexpected accounts %+v", accounts)
+	}
+
+	mock := &solana.MockRPC{
+		GetAccountInfoFunc: func(ctx context.Context, address solana.Pubkey, config *solana.GetAccountInfoConfig) (solana.AccountInfoResponse, error) {
+			var resp solana.AccountInfoResponse
+			resp.Value.Owner = ProgramID
+			resp.Value.Data.Bytes = data
+			return resp, nil
+		},
+	}
+	counter, err := FetchCounter(context.Background(), mock, key(3))
+	if err != nil || counter.Authority != key(1) {
+		t.Fatalf("FetchCounter = %+v, %v", counter, err)
+	}
+}
+`
+
+func TestAnchorGenCompiles(t *testing.T) {
+	if testing.Short() {
+		t.Skip("builds the generated package")
+	}
+	goTool, err := exec.LookPath("go")
+	if err != nil {
+		t.Skip("go tool not found")
+	}
+	src, err := anchorgen.Generate(parseTestIDL(t, currentIDL, testKey(9)), "")
+	if err != nil {
+		t.Fatalf("Generate failed: %v", err)
+	}
+
+	// The package must be inside the module to import it.
+	dir, err := os.MkdirTemp(".", "anchorgen-")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer os.RemoveAll(dir)
+	if err := os.WriteFile(filepath.Join(dir, "counter.go"), src, 0o644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(filepath.Join(dir, "counter_test.go"), []byte(generatedCounterTest), 0o644); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, args := range [][]string{{"vet", "./" + dir}, {"test", "./" + dir}} {
+		out, err := exec.Command(goTool, args...).CombinedOutput()
+		if err != nil {
+			t.Fatalf("go %s failed: %v\n%s", args[0], err, out)
+		}
+	}
+}
@@ -0,0 +1,140 @@
+package solana_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	solana "github.com/solana-rpc/client"
+)
+
+func newBlockhashServer(t *testing.T, fetches *int32) string {
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		n := atomic.AddInt32(fetches, 1)
+		return map[string]interface{}{
+			"context": map[string]interface{}{"slot": 100 + n},
+			"value": map[string]interface{}{
+				"blockhash":            fmt.Sprintf("hash%d", n),
+				"lastValidBlockHeight": 1000 + n,
+			},
+		}, nil
+	})
+	return server.URL
+}
+
+func TestBlockhashProviderRefreshes(t *testing.T) {
+	var fetches int32
+	client := solana.NewClient(newBlockhashServer(t, &fetches))
+	provider := solana.NewBlockhashProvider(client, &solana.BlockhashProviderConfig{RefreshInterval: 20 * time.Millisecond})
+	defer provider.Close()
+
+	first, err := provider.Current(context.Background())
+	if err != nil {
+		t.Fatalf("Current failed: %v", err)
+	}
+	if first.Blockhash == "" || first.LastValidBlockHeight <= 1000 {
+		t.Fatalf("unexpected blockhash %+v", first)
+	}
+
+	time.Sleep(100 * time.Millisecond)
+	latest, err := provider.Current(context.Background())
+	if err != nil {
+		t.Fatalf("Current failed: %v", err)
+	}
+	if latest.Blockhash == first.Blockhash {
+		t.Fatal("expected the background refresh to replace the blockhash")
+	}
+}
+
+func TestBlockhashProviderServesFromCache(t *testing.T) {
+	var fetches int32
+	client := solana.NewClient(newBlockhashServer(t, &fetches))
+	provider := solana.NewBlockhashProvider(client, &solana.BlockhashProviderConfig{RefreshInterval: time.Hour})
+	defer provider.Close()
+
+	for i := 0; i < 10; i++ {
+		if _, err := provider.Current(context.Background()); err != nil {
+			t.Fatalf("Current failed: %v", err)
+		}
+	}
+	if n := atomic.LoadInt32(&fetches); n != 1 {
+		t.Fatalf("expected 1 fetch, got %d", n)
+	}
+}
+
+func TestBlockhashProviderFetchesWhenStale(t *testing.T) {
+	var fetches int32
+	client := solana.NewClient(newBlockhashServer(t, &fetches))
+	provider := solana.NewBlockhashProvider(client, &solana.BlockhashProviderConfig{
+		RefreshInterval: time.Hour,
+		MaxAge:          10 * time.Millisecond,
+	})
+	defer provider.Close()
+
+	if _, err := provider.Current(context.Background()); err != nil {
+		t.Fatalf("Current failed: %v", err)
+	}
+	time.Sleep(20 * time.Millisecond)
+	if _, err := provider.Current(context.Background()); err != nil {
+		t.Fatalf("Current failed: %v", err)
+	}
+	if n := atomic.LoadInt32(&fetches); n != 2 {
+		t.Fatalf("expected a synchronous fetch once stale, got %d fetches", n)
+	}
+}
+
+func TestBlockhashProviderWithMockRPC(t *testing.T) {
+	mock := &solana.MockRPC{
+		GetLatestBlockhashFunc: func(ctx context.Context, config *solana.CommitmentConfig) (solana.LatestBlockhashResponse, error) {
+			return solana.LatestBlockhashResponse{Value: solana.LatestBlockhash{Blockhash: "mocked", LastValidBlockHeight: 10}}, nil
+		},
+	}
+	provider := solana.NewBlockhashProvider(mock, &solana.BlockhashProviderConfig{RefreshInterval: time.Hour})
+	defer provider.Close()
+
+	got, err := provider.Current(context.Background())
+	if err != nil || got.Blockhash != "mocked" {
+		t.Fatalf("Current = %+v, %v", got, err)
+	}
+	if len(mock.CallsTo("GetLatestBlockhash")) == 0 {
+		t.Fatal("expected the provider to call the mock")
+	}
+}
+
+func TestBlockhashProviderCurrentRespectsContext(t *testing.T) {
+	var calls int32
+	mock := &solana.MockRPC{
+		GetLatestBlockhashFunc: func(ctx context.Context, config *solana.CommitmentConfig) (solana.LatestBlockhashResponse, error) {
+			if atomic.AddInt32(&calls, 1) == 1 {
+				// The first background fetch hangs until its deadline.
+				<-ctx.Done()
+				return solana.LatestBlockhashResponse{}, ctx.Err()
+			}
+			return solana.LatestBlockhashResponse{Value: solana.LatestBlockhash{Blockhash: "late", LastValidBlockHeight: 10}}, nil
+		},
+	}
+	provider := solana.NewBlockhashProvider(mock, &solana.BlockhashProviderConfig{RefreshInterval: 200 * time.Millisecond})
+	defer provider.Close()
+
+	for atomic.LoadInt32(&calls) == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := provider.Current(ctx); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected the deadline to end the wait, got %v", err)
+	}
+	if elapsed := time.Since(start); elapsed > 150*time.Millisecond {
+		t.Fatalf("Current waited %v for the hung fetch", elapsed)
+	}
+
+	// The hung fetch times out after RefreshInterval and no longer blocks.
+	got, err := provider.Current(context.Background())
+	if err != nil || got.Blockhash != "late" {
+		t.Fatalf("Current = %+v, %v", got, err)
+	}
+}
@@ -0,0 +1,164 @@
+package solana_test
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	solana "github.com/solana-rpc/client"
+)
+
+type methodCounter struct {
+	mu     sync.Mutex
+	counts map[string]int
+}
+
+func (m *methodCounter) add(method string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.counts == nil {
+		m.counts = make(map[string]int)
+	}
+	m.counts[method]++
+}
+
+func (m *methodCounter) get(method string) int {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.counts[method]
+}
+
+func TestCacheImmutableResults(t *testing.T) {
+	var counter methodCounter
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		counter.add(call.Method)
+		switch call.Method {
+		case "getGenesisHash":
+			return "genesis", nil
+		case "getBlock":
+			return map[string]interface{}{"blockhash": "hash"}, nil
+		}
+		return nil, nil
+	})
+	client := solana.NewClient(server.URL, solana.WithCache(solana.NewLRUCache(100), nil))
+	ctx := context.Background()
+
+	for i := 0; i < 3; i++ {
+		if hash, err := client.GetGenesisHash(ctx); err != nil || hash != "genesis" {
+			t.Fatalf("GetGenesisHash = %q, %v", hash, err)
+		}
+		if _, err := client.GetBlock(ctx, 10, nil); err != nil {
+			t.Fatalf("GetBlock failed: %v", err)
+		}
+		if _, err := client.GetBlock(ctx, 10, &solana.GetBlockConfig{Commitment: solana.CommitmentConfirmed}); err != nil {
+			t.Fatalf("GetBlock failed: %v", err)
+		}
+	}
+
+	if n := counter.get("getGenesisHash"); n != 1 {
+		t.Fatalf("getGenesisHash sent %d times, want 1", n)
+	}
+	// Finalized blocks are cached; confirmed ones may still change.
+	if n := counter.get("getBlock"); n != 4 {
+		t.Fatalf("getBlock sent %d times, want 4", n)
+	}
+}
+
+func TestCacheInvalidatesOnSlotAdvance(t *testing.T) {
+	var counter methodCounter
+	slot := 100
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		counter.add(call.Method)
+		var value interface{}
+		if call.Method == "getBalance" {
+			slot++
+			value = 5
+		}
+		return map[string]interface{}{"context": map[string]interface{}{"slot": slot}, "value": value}, nil
+	})
+	client := solana.NewClient(server.URL, solana.WithCache(solana.NewLRUCache(100), solana.CachePolicy{
+		"getAccountInfo": {TTL: time.Minute, InvalidateOnSlot: true},
+	}))
+	ctx := context.Background()
+	config := &solana.GetAccountInfoConfig{Commitment: solana.CommitmentProcessed}
+
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetAccountInfo(ctx, tokenProgramID, config); err != nil {
+			t.Fatalf("GetAccountInfo failed: %v", err)
+		}
+	}
+	if n := counter.get("getAccountInfo"); n != 1 {
+		t.Fatalf("getAccountInfo sent %d times before the slot advanced, want 1", n)
+	}
+
+	if _, err := client.GetBalance(ctx, tokenProgramID, nil); err != nil {
+		t.Fatalf("GetBalance failed: %v", err)
+	}
+	if _, err := client.GetAccountInfo(ctx, tokenProgramID, config); err != nil {
+		t.Fatalf("GetAccountInfo failed: %v", err)
+	}
+	if n := counter.get("getAccountInfo"); n != 2 {
+		t.Fatalf("getAccountInfo sent %d times after the slot advanced, want 2", n)
+	}
+}
+
+func TestCacheUsesResponseSlot(t *testing.T) {
+	var counter methodCounter
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		counter.add(call.Method)
+		switch call.Method {
+		case "getBalance":
+			return map[string]interface{}{"context": map[string]interface{}{"slot": 200}, "value": 5}, nil
+		case "getAccountInfo":
+			// A lagging node answers from an older slot.
+			return map[string]interface{}{"context": map[string]interface{}{"slot": 150}, "value": nil}, nil
+		}
+		return 7, nil
+	})
+	client := solana.NewClient(server.URL, solana.WithCache(solana.NewLRUCache(100), solana.CachePolicy{
+		"getAccountInfo": {TTL: time.Minute, InvalidateOnSlot: true},
+		"getSlot":        {TTL: time.Minute, InvalidateOnSlot: true},
+	}))
+	ctx := context.Background()
+
+	if _, err := client.GetBalance(ctx, tokenProgramID, nil); err != nil {
+		t.Fatalf("GetBalance failed: %v", err)
+	}
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetAccountInfo(ctx, tokenProgramID, nil); err != nil {
+			t.Fatalf("GetAccountInfo failed: %v", err)
+		}
+		if _, err := client.GetSlot(ctx, nil); err != nil {
+			t.Fatalf("GetSlot failed: %v", err)
+		}
+	}
+	if n := counter.get("getAccountInfo"); n != 2 {
+		t.Fatalf("getAccountInfo from an older slot sent %d times, want 2", n)
+	}
+	if n := counter.get("getSlot"); n != 2 {
+		t.Fatalf("getSlot without a context slot sent %d times, want 2", n)
+	}
+}
+
+func TestLRUCache(t *testing.T) {
+	ctx := context.Background()
+	cache := solana.NewLRUCache(2)
+	cache.Set(ctx, "a", []byte("1"), 0)
+	cache.Set(ctx, "b", []byte("2"), 0)
+	cache.Get(ctx, "a")
+	cache.Set(ctx, "c", []byte("3"), 0)
+
+	if _, ok := cache.Get(ctx, "b"); ok {
+		t.Fatal("expected least recently used entry to be evicted")
+	}
+	if v, ok := cache.Get(ctx, "a"); !ok || string(v) != "1" {
+		t.Fatalf("Get(a) = %q, %v", v, ok)
+	}
+
+	cache.Set(ctx, "d", []byte("4"), time.Millisecond)
+	time.Sleep(5 * time.Millisecond)
+	if _, ok := cache.Get(ctx, "d"); ok {
+		t.Fatal("expected expired entry to miss")
+	}
+}
@@ -0,0 +1,44 @@
+package solana_test
+
+import (
+	"context"
+	"testing"
+
+	solana "github.com/solana-rpc/client"
+)
+
+func TestCallUnlistedMethod(t *testing.T) {
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		if call.Method != "getPriorityFeeEstimate" {
+			return nil, &rpcErrorBody{Code: -32601, Message: "Method not found"}
+		}
+		return map[string]interface{}{
+			"context": map[string]interface{}{"slot": 7},
+			"value":   map[string]interface{}{"priorityFeeEstimate": 1200},
+		}, nil
+	})
+	client := solana.NewClient(server.URL)
+	ctx := context.Background()
+
+	var result solana.Response[struct {
+		PriorityFeeEstimate int64 `json:"priorityFeeEstimate"`
+	}]
+	if err := client.Call(ctx, "getPriorityFeeEstimate", []interface{}{}, &result); err != nil {
+		t.Fatalf("Call failed: %v", err)
+	}
+	if result.Context.Slot != 7 || result.Value.PriorityFeeEstimate != 1200 {
+		t.Fatalf("unexpected result: %+v", result)
+	}
+
+	raw, err := client.CallRaw(ctx, "getPriorityFeeEstimate", nil)
+	if err != nil {
+		t.Fatalf("CallRaw failed: %v", err)
+	}
+	if len(raw) == 0 || raw[0] != '{' {
+		t.Fatalf("unexpected raw result %s", raw)
+	}
+
+	if _, err := client.CallRaw(ctx, "noSuchMethod", nil); err == nil {
+		t.Fatal("expected error for unknown method")
+	}
+}
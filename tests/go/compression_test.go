@@ -0,0 +1,138 @@
+package solana_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"compress/zlib"
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	solana "github.com/solana-rpc/client"
+)
+
+func newCompressingServer(t *testing.T, encoding string, result interface{}) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if !strings.Contains(r.Header.Get("Accept-Encoding"), encoding) {
+			t.Errorf("Accept-Encoding %q does not offer %s", r.Header.Get("Accept-Encoding"), encoding)
+		}
+		var call rpcCall
+		if err := json.NewDecoder(r.Body).Decode(&call); err != nil {
+			t.Errorf("decode request: %v", err)
+		}
+		body, _ := json.Marshal(map[string]interface{}{"jsonrpc": "2.0", "id": call.ID, "result": result})
+
+		var buf bytes.Buffer
+		var zw io.WriteCloser
+		switch encoding {
+		case "gzip":
+			zw = gzip.NewWriter(&buf)
+		case "deflate":
+			zw = zlib.NewWriter(&buf)
+		}
+		zw.Write(body)
+		zw.Close()
+
+		w.Header().Set("Content-Encoding", encoding)
+		w.Write(buf.Bytes())
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+func TestCompressedResponses(t *testing.T) {
+	hash := strings.Repeat("1", 4096)
+	for _, encoding := range []string{"gzip", "deflate"} {
+		t.Run(encoding, func(t *testing.T) {
+			client := solana.NewClient(newCompressingServer(t, encoding, hash).URL)
+			got, err := client.GetGenesisHash(context.Background())
+			if err != nil {
+				t.Fatalf("GetGenesisHash failed: %v", err)
+			}
+			if got != hash {
+				t.Fatalf("unexpected result of length %d", len(got))
+			}
+
+			stats := client.CompressionStats()
+			if stats.Responses != 1 || stats.CompressedResponses != 1 {
+				t.Fatalf("unexpected counts: %+v", stats)
+			}
+			if stats.SavedBytes() <= 0 || stats.Ratio() <= 1 {
+				t.Fatalf("expected savings, got %+v", stats)
+			}
+		})
+	}
+}
+
+func TestCompressedResponseLimitAppliesToDecodedSize(t *testing.T) {
+	server := newCompressingServer(t, "gzip", strings.Repeat("1", 4096))
+	client := solana.NewClient(server.URL, solana.WithDefaultMaxResponseBytes(1024))
+	if _, err := client.GetGenesisHash(context.Background()); err == nil {
+		t.Fatal("expected the decompressed body to exceed the limit")
+	}
+}
+
+func TestWithoutCompression(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if got := r.Header.Get("Accept-Encoding"); strings.Contains(got, "deflate") {
+			t.Errorf("unexpected Accept-Encoding %q", got)
+		}
+		w.Write([]byte(`{"jsonrpc":"2.0","id":1,"result":"hash"}`))
+	}))
+	defer server.Close()
+
+	client := solana.NewClient(server.URL, solana.WithoutCompression())
+	if _, err := client.GetGenesisHash(context.Background()); err != nil {
+		t.Fatalf("GetGenesisHash failed: %v", err)
+	}
+	if stats := client.CompressionStats(); stats.CompressedResponses != 0 || stats.SavedBytes() != 0 {
+		t.Fatalf("unexpected stats: %+v", stats)
+	}
+}
+
+type closeTracker struct {
+	io.ReadCloser
+	closed *int
+}
+
+func (c closeTracker) Close() error {
+	*c.closed++
+	return c.ReadCloser.Close()
+}
+
+func TestDecompressorClosedAndCountedOnSuccess(t *testing.T) {
+	closed := 0
+	track := solana.WithDecompressor("gzip", func(r io.Reader) (io.ReadCloser, error) {
+		zr, err := gzip.NewReader(r)
+		if err != nil {
+			return nil, err
+		}
+		return closeTracker{zr, &closed}, nil
+	})
+
+	client := solana.NewClient(newCompressingServer(t, "gzip", strings.Repeat("1", 4096)).URL, track)
+	if _, err := client.GetGenesisHash(context.Background()); err != nil {
+		t.Fatalf("GetGenesisHash failed: %v", err)
+	}
+	if closed != 1 {
+		t.Fatalf("expected the decompressor to be closed once, got %d", closed)
+	}
+
+	// A body that fails the limit is not counted at all.
+	limited := solana.NewClient(newCompressingServer(t, "gzip", strings.Repeat("1", 4096)).URL,
+		track, solana.WithDefaultMaxResponseBytes(1024))
+	if _, err := limited.GetGenesisHash(context.Background()); err == nil {
+		t.Fatal("expected the decompressed body to exceed the limit")
+	}
+	if stats := limited.CompressionStats(); stats.Responses != 0 || stats.CompressedResponses != 0 {
+		t.Fatalf("unexpected stats for a failed read: %+v", stats)
+	}
+	if closed != 2 {
+		t.Fatalf("expected the decompressor to be closed after a failed read, got %d", closed)
+	}
+}
@@ -0,0 +1,160 @@
+package solana_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	solana "github.com/solana-rpc/client"
+)
+
+func testKey(b byte) solana.Pubkey {
+	key := make([]byte, 32)
+	key[31] = b
+	return solana.EncodeBase58(key)
+}
+
+func transferTransaction(versioned bool) *solana.Transaction {
+	msg := solana.Message{
+		Versioned:       versioned,
+		Header:          solana.MessageHeader{NumRequiredSignatures: 1, NumReadonlyUnsignedAccounts: 1},
+		AccountKeys:     []solana.Pubkey{testKey(1), testKey(2), testKey(0)},
+		RecentBlockhash: testKey(9),
+		Instructions: []solana.CompiledInstruction{
+			{ProgramIDIndex: 2, Accounts: []uint8{0, 1}, Data: []byte{2, 0, 0, 0, 1, 0, 0, 0, 0, 0, 0, 0}},
+		},
+	}
+	if versioned {
+		msg.AddressTableLookups = []solana.AddressTableLookup{{AccountKey: testKey(7), WritableIndexes: []uint8{4}}}
+		msg.Instructions[0].Accounts = append(msg.Instructions[0].Accounts, 3)
+	}
+	sig := make([]byte, 64)
+	sig[0] = 1
+	return &solana.Transaction{Signatures: []solana.Signature{solana.EncodeBase58(sig)}, Message: msg}
+}
+
+func TestTransactionWireRoundTrip(t *testing.T) {
+	for _, versioned := range []bool{false, true} {
+		tx := transferTransaction(versioned)
+		wire, err := tx.MarshalBinary()
+		if err != nil {
+			t.Fatalf("MarshalBinary failed: %v", err)
+		}
+		var back solana.Transaction
+		if err := back.UnmarshalBinary(wire); err != nil {
+			t.Fatalf("UnmarshalBinary failed: %v", err)
+		}
+		if !reflect.DeepEqual(&back, tx) {
+			t.Fatalf("round trip mismatch:\n got %+v\nwant %+v", back, *tx)
+		}
+	}
+}
+
+func TestEstimateComputeUnits(t *testing.T) {
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		var encoded string
+		var config map[string]interface{}
+		json.Unmarshal(call.Params[0], &encoded)
+		json.Unmarshal(call.Params[1], &config)
+		if config["replaceRecentBlockhash"] != true || config["sigVerify"] == true || config["encoding"] != "base64" {
+			t.Errorf("unexpected simulate config %v", config)
+		}
+
+		wire, _ := base64.StdEncoding.DecodeString(encoded)
+		var tx solana.Transaction
+		if err := tx.UnmarshalBinary(wire); err != nil {
+			t.Errorf("simulated transaction does not decode: %v", err)
+		}
+		limit := solana.SetComputeUnitLimit(solana.MaxComputeUnitLimit).Data
+		if !bytes.Equal(tx.Message.Instructions[0].Data, limit) {
+			t.Errorf("simulated without the maximum limit: %x", tx.Message.Instructions[0].Data)
+		}
+		return map[string]interface{}{
+			"context": map[string]interface{}{"slot": 1},
+			"value":   map[string]interface{}{"err": nil, "logs": []string{}, "unitsConsumed": 1000},
+		}, nil
+	})
+	client := solana.NewClient(server.URL)
+
+	for _, versioned := range []bool{false, true} {
+		tx := transferTransaction(versioned)
+		est, err := solana.EstimateComputeUnits(context.Background(), client, tx, nil)
+		if err != nil {
+			t.Fatalf("EstimateComputeUnits failed: %v", err)
+		}
+		if est.UnitsConsumed != 1000 || est.Limit != 1100 {
+			t.Fatalf("unexpected estimate %+v", est)
+		}
+
+		msg := est.Transaction.Message
+		if n := len(msg.AccountKeys); n != 4 || msg.AccountKeys[3] != solana.ComputeBudgetProgramID {
+			t.Fatalf("compute budget program not appended: %v", msg.AccountKeys)
+		}
+		if msg.Header.NumReadonlyUnsignedAccounts != 2 {
+			t.Fatalf("unexpected header %+v", msg.Header)
+		}
+		if ix := msg.Instructions[0]; ix.ProgramIDIndex != 3 || !bytes.Equal(ix.Data, solana.SetComputeUnitLimit(1100).Data) {
+			t.Fatalf("unexpected limit instruction %+v", ix)
+		}
+		want := []uint8{0, 1}
+		if versioned {
+			// The lookup table address moved from index 3 to 4.
+			want = append(want, 4)
+		}
+		if got := msg.Instructions[1].Accounts; !bytes.Equal(got, want) {
+			t.Fatalf("transfer accounts = %v, want %v", got, want)
+		}
+		if est.Transaction.Signatures[0] != solana.EncodeBase58(make([]byte, 64)) {
+			t.Fatal("expected the rebuilt transaction's signatures to be zeroed")
+		}
+		if len(tx.Message.Instructions) != 1 {
+			t.Fatal("input transaction was modified")
+		}
+
+		again, err := solana.WithComputeUnitLimit(est.Transaction, 5000)
+		if err != nil {
+			t.Fatalf("WithComputeUnitLimit failed: %v", err)
+		}
+		if len(again.Message.Instructions) != 2 || !bytes.Equal(again.Message.Instructions[0].Data, solana.SetComputeUnitLimit(5000).Data) {
+			t.Fatalf("expected the existing limit to be replaced: %+v", again.Message.Instructions)
+		}
+	}
+}
+
+func TestEstimateComputeUnitsSimulationError(t *testing.T) {
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		return map[string]interface{}{
+			"context": map[string]interface{}{"slot": 1},
+			"value":   map[string]interface{}{"err": "AccountNotFound", "logs": []string{"log"}},
+		}, nil
+	})
+	client := solana.NewClient(server.URL)
+
+	_, err := solana.EstimateComputeUnits(context.Background(), client, transferTransaction(false), nil)
+	if !errors.Is(err, solana.ErrSimulationFailed) {
+		t.Fatalf("expected ErrSimulationFailed, got %v", err)
+	}
+	var simErr *solana.SimulationError
+	if !errors.As(err, &simErr) || len(simErr.Logs) != 1 {
+		t.Fatalf("unexpected error details: %v", err)
+	}
+}
+
+func TestEstimateComputeUnitsWithMockRPC(t *testing.T) {
+	mock := &solana.MockRPC{
+		SimulateTransactionFunc: func(ctx context.Context, transaction string, config *solana.SimulateTransactionConfig) (solana.SimulateTransactionResponse, error) {
+			return solana.SimulateTransactionResponse{Value: solana.SimulateTransactionResult{UnitsConsumed: 500}}, nil
+		},
+	}
+	est, err := solana.EstimateComputeUnits(context.Background(), mock, transferTransaction(false), &solana.EstimateComputeUnitsConfig{Margin: -1})
+	if err != nil {
+		t.Fatalf("EstimateComputeUnits failed: %v", err)
+	}
+	if est.Limit != 500 {
+		t.Fatalf("unexpected estimate %+v", est)
+	}
+}
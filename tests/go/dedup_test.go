@@ -0,0 +1,181 @@
+package solana_test
+
+import (
+	"context"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	solana "github.com/solana-rpc/client"
+)
+
+func TestDeduplicationCollapsesIdenticalCalls(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		return 42, nil
+	})
+	client := solana.NewClient(server.URL, solana.WithDeduplication())
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make([]solana.Slot, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			// An omitted config and an empty one are the same call.
+			var config *solana.CommitmentConfig
+			if i%2 == 0 {
+				config = &solana.CommitmentConfig{}
+			}
+			slot, err := client.GetSlot(context.Background(), config)
+			if err != nil {
+				t.Errorf("GetSlot failed: %v", err)
+			}
+			results[i] = slot
+		}(i)
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&requests); n != 1 {
+		t.Fatalf("expected 1 request, got %d", n)
+	}
+	for i, slot := range results {
+		if slot != 42 {
+			t.Fatalf("caller %d got slot %d", i, slot)
+		}
+	}
+}
+
+func TestDeduplicationSkipsWrites(t *testing.T) {
+	var requests int32
+	release := make(chan struct{})
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		atomic.AddInt32(&requests, 1)
+		<-release
+		return "sig", nil
+	})
+	client := solana.NewClient(server.URL, solana.WithDeduplication())
+
+	var wg sync.WaitGroup
+	for i := 0; i < 3; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.SendTransaction(context.Background(), "AQ==", nil); err != nil {
+				t.Errorf("SendTransaction failed: %v", err)
+			}
+		}()
+	}
+	time.Sleep(50 * time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	if n := atomic.LoadInt32(&requests); n != 3 {
+		t.Fatalf("expected 3 requests, got %d", n)
+	}
+}
+
+func TestDeduplicationCallerCancellation(t *testing.T) {
+	release := make(chan struct{})
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		<-release
+		return 7, nil
+	})
+	client := solana.NewClient(server.URL, solana.WithDeduplication())
+
+	done := make(chan solana.Slot)
+	go func() {
+		slot, err := client.GetSlot(context.Background(), nil)
+		if err != nil {
+			t.Errorf("GetSlot failed: %v", err)
+		}
+		done <- slot
+	}()
+	time.Sleep(20 * time.Millisecond)
+
+	// A caller giving up does not cancel the call for the others.
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetSlot(ctx, nil); err == nil {
+		t.Fatal("expected the cancelled caller to fail")
+	}
+	close(release)
+	if slot := <-done; slot != 7 {
+		t.Fatalf("unexpected slot %d", slot)
+	}
+}
+
+type tenantKey struct{}
+
+func TestDeduplicationKeepsDistinctCallsApart(t *testing.T) {
+	tenant := func(ctx context.Context, req *solana.Request, next solana.Invoker) (*solana.RawResponse, error) {
+		if name, ok := ctx.Value(tenantKey{}).(string); ok {
+			req.Header.Set("X-Tenant", name)
+		}
+		return next(ctx, req)
+	}
+	bg := context.Background()
+	cases := map[string]struct {
+		a, b context.Context
+		call func(ctx context.Context, client *solana.Client) error
+	}{
+		"headers": {
+			context.WithValue(bg, tenantKey{}, "a"), context.WithValue(bg, tenantKey{}, "b"),
+			func(ctx context.Context, client *solana.Client) error {
+				_, err := client.GetSlot(ctx, nil)
+				return err
+			},
+		},
+		"response size cap": {
+			bg, solana.WithMaxResponseBytes(bg, 0),
+			func(ctx context.Context, client *solana.Client) error {
+				_, err := client.GetSlot(ctx, nil)
+				return err
+			},
+		},
+		"unknown method": {
+			bg, bg,
+			func(ctx context.Context, client *solana.Client) error {
+				_, err := client.CallRaw(ctx, "getSomethingNew", nil)
+				return err
+			},
+		},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			var requests int32
+			release := make(chan struct{})
+			server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+				atomic.AddInt32(&requests, 1)
+				<-release
+				return 1, nil
+			})
+			client := solana.NewClient(server.URL, solana.WithDeduplication(), solana.WithInterceptors(tenant))
+
+			var wg sync.WaitGroup
+			for _, ctx := range []context.Context{tc.a, tc.b} {
+				wg.Add(1)
+				go func(ctx context.Context) {
+					defer wg.Done()
+					if err := tc.call(ctx, client); err != nil {
+						t.Errorf("call failed: %v", err)
+					}
+				}(ctx)
+			}
+			time.Sleep(50 * time.Millisecond)
+			close(release)
+			wg.Wait()
+
+			if n := atomic.LoadInt32(&requests); n != 2 {
+				t.Fatalf("expected 2 requests, got %d", n)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package solana_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	solana "github.com/solana-rpc/client"
+)
+
+func TestDefaultCommitment(t *testing.T) {
+	var got []solana.CommitmentConfig
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		var cfg solana.CommitmentConfig
+		if len(call.Params) > 0 {
+			json.Unmarshal(call.Params[len(call.Params)-1], &cfg)
+		}
+		got = append(got, cfg)
+		return 1, nil
+	})
+
+	client := solana.NewClient(server.URL, solana.WithDefaultCommitment(solana.CommitmentConfirmed))
+	ctx := context.Background()
+
+	explicit := &solana.CommitmentConfig{Commitment: solana.CommitmentFinalized}
+	client.GetSlot(ctx, nil)
+	client.GetSlot(solana.WithCommitment(ctx, solana.CommitmentProcessed), nil)
+	client.GetSlot(solana.WithCommitment(ctx, solana.CommitmentProcessed), explicit)
+	client.GetSlot(ctx, &solana.CommitmentConfig{MinContextSlot: 5})
+
+	want := []solana.Commitment{
+		solana.CommitmentConfirmed,
+		solana.CommitmentProcessed,
+		solana.CommitmentFinalized,
+		solana.CommitmentConfirmed,
+	}
+	if len(got) != len(want) {
+		t.Fatalf("expected %d requests, got %d", len(want), len(got))
+	}
+	for i := range want {
+		if got[i].Commitment != want[i] {
+			t.Errorf("request %d: expected commitment %q, got %q", i, want[i], got[i].Commitment)
+		}
+	}
+	if got[3].MinContextSlot != 5 {
+		t.Errorf("expected caller's MinContextSlot to be kept, got %d", got[3].MinContextSlot)
+	}
+	if explicit.Commitment != solana.CommitmentFinalized {
+		t.Errorf("caller's config was modified")
+	}
+}
+
+func TestNoDefaultCommitmentSendsNoConfig(t *testing.T) {
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		if len(call.Params) != 0 {
+			t.Errorf("expected no params, got %d", len(call.Params))
+		}
+		return 1, nil
+	})
+	if _, err := solana.NewClient(server.URL).GetSlot(context.Background(), nil); err != nil {
+		t.Fatalf("GetSlot failed: %v", err)
+	}
+}
+
+func TestDefaultProcessedRaisedForBlockMethods(t *testing.T) {
+	got := map[string]solana.Commitment{}
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		var cfg solana.CommitmentConfig
+		json.Unmarshal(call.Params[len(call.Params)-1], &cfg)
+		got[call.Method] = cfg.Commitment
+		return nil, nil
+	})
+
+	client := solana.NewClient(server.URL, solana.WithDefaultCommitment(solana.CommitmentProcessed))
+	ctx := context.Background()
+	client.GetBlocks(ctx, 1, nil, nil)
+	client.GetTransaction(ctx, "sig", nil)
+	client.GetBalance(ctx, tokenProgramID, nil)
+	client.GetBlock(ctx, 1, &solana.GetBlockConfig{Commitment: solana.CommitmentProcessed})
+
+	want := map[string]solana.Commitment{
+		"getBlocks":      solana.CommitmentConfirmed,
+		"getTransaction": solana.CommitmentConfirmed,
+		"getBalance":     solana.CommitmentProcessed,
+		"getBlock":       solana.CommitmentProcessed,
+	}
+	for method, commitment := range want {
+		if got[method] != commitment {
+			t.Errorf("%s: expected commitment %q, got %q", method, commitment, got[method])
+		}
+	}
+}
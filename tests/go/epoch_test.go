@@ -0,0 +1,111 @@
+package solana_test
+
+import (
+	"testing"
+	"time"
+
+	solana "github.com/solana-rpc/client"
+)
+
+// warmupSchedule is the schedule of a cluster started with warmup and 8192
+// slots per epoch, as on a local test validator.
+var warmupSchedule = solana.EpochSchedule{
+	SlotsPerEpoch:            8192,
+	LeaderScheduleSlotOffset: 8192,
+	Warmup:                   true,
+	FirstNormalEpoch:         8,
+	FirstNormalSlot:          8160,
+}
+
+func TestEpochScheduleWarmup(t *testing.T) {
+	cases := []struct {
+		slot      solana.Slot
+		epoch     int64
+		slotIndex int64
+	}{
+		{0, 0, 0},
+		{31, 0, 31},
+		{32, 1, 0},
+		{95, 1, 63},
+		{96, 2, 0},
+		{8159, 7, 4095},
+		{8160, 8, 0},
+		{8160 + 8192 + 5, 9, 5},
+	}
+	for _, tc := range cases {
+		epoch, index := warmupSchedule.EpochForSlot(tc.slot)
+		if epoch != tc.epoch || index != tc.slotIndex {
+			t.Errorf("EpochForSlot(%d) = (%d, %d), want (%d, %d)", tc.slot, epoch, index, tc.epoch, tc.slotIndex)
+		}
+	}
+
+	if n := warmupSchedule.SlotsInEpoch(3); n != 256 {
+		t.Errorf("SlotsInEpoch(3) = %d, want 256", n)
+	}
+	if first := warmupSchedule.FirstSlotInEpoch(9); first != 16352 {
+		t.Errorf("FirstSlotInEpoch(9) = %d, want 16352", first)
+	}
+	if last := warmupSchedule.LastSlotInEpoch(7); last != 8159 {
+		t.Errorf("LastSlotInEpoch(7) = %d, want 8159", last)
+	}
+	if epoch := warmupSchedule.LeaderScheduleEpoch(40); epoch != 2 {
+		t.Errorf("LeaderScheduleEpoch(40) = %d, want 2", epoch)
+	}
+	if epoch := warmupSchedule.LeaderScheduleEpoch(8160); epoch != 9 {
+		t.Errorf("LeaderScheduleEpoch(8160) = %d, want 9", epoch)
+	}
+
+	// Every slot lies between the first and last slot of its epoch.
+	for slot := solana.Slot(0); slot < 30000; slot++ {
+		epoch, index := warmupSchedule.EpochForSlot(slot)
+		first := warmupSchedule.FirstSlotInEpoch(epoch)
+		if first+index != slot || slot > warmupSchedule.LastSlotInEpoch(epoch) {
+			t.Fatalf("slot %d: epoch %d starts at %d with index %d", slot, epoch, first, index)
+		}
+	}
+}
+
+func TestEpochScheduleWithoutWarmup(t *testing.T) {
+	mainnet := solana.EpochSchedule{SlotsPerEpoch: 432000, LeaderScheduleSlotOffset: 432000}
+	if epoch, index := mainnet.EpochForSlot(432000*5 + 7); epoch != 5 || index != 7 {
+		t.Fatalf("EpochForSlot = (%d, %d), want (5, 7)", epoch, index)
+	}
+	if first := mainnet.FirstSlotInEpoch(5); first != 432000*5 {
+		t.Fatalf("FirstSlotInEpoch(5) = %d", first)
+	}
+	if epoch := mainnet.LeaderScheduleEpoch(432000*5 + 7); epoch != 6 {
+		t.Fatalf("LeaderScheduleEpoch = %d, want 6", epoch)
+	}
+}
+
+func TestEpochScheduleWithoutSlotsPerEpoch(t *testing.T) {
+	var empty solana.EpochSchedule
+	if err := empty.Validate(); err == nil {
+		t.Fatal("expected Validate to reject a schedule without slots per epoch")
+	}
+	// Malformed schedules give meaningless results but must not panic.
+	empty.EpochForSlot(1000)
+	empty.LeaderScheduleEpoch(1000)
+}
+
+func TestEpochProgress(t *testing.T) {
+	info := solana.EpochInfo{Epoch: 500, SlotIndex: 108000, SlotsInEpoch: 432000}
+	samples := []solana.PerformanceSample{
+		{NumSlots: 120, SamplePeriodSecs: 60},
+		{NumSlots: 150, SamplePeriodSecs: 60},
+	}
+	p := info.EpochProgress(samples)
+	if p.Fraction != 0.25 || p.SlotsRemaining != 324000 {
+		t.Fatalf("unexpected progress %+v", p)
+	}
+	if want := 120 * time.Second / 270; p.SlotDuration != want {
+		t.Fatalf("SlotDuration = %v, want %v", p.SlotDuration, want)
+	}
+	if want := 324000 * (120 * time.Second / 270); p.Remaining != want {
+		t.Fatalf("Remaining = %v, want %v", p.Remaining, want)
+	}
+
+	if p := info.EpochProgress(nil); p.SlotDuration != 400*time.Millisecond {
+		t.Fatalf("expected the nominal slot time without samples, got %v", p.SlotDuration)
+	}
+}
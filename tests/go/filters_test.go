@@ -0,0 +1,124 @@
+package solana_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	solana "github.com/solana-rpc/client"
+)
+
+const tokenProgramID = "TokenkegQfeZyiNwAJbNbGKPFXCWuBvf9Ss623VQ5DA"
+
+func TestBase58RoundTrip(t *testing.T) {
+	inputs := [][]byte{
+		{},
+		{0, 0, 1},
+		bytes.Repeat([]byte{0xff}, 32),
+		[]byte("hello world"),
+	}
+	for _, in := range inputs {
+		out, err := solana.DecodeBase58(solana.EncodeBase58(in))
+		if err != nil {
+			t.Fatalf("DecodeBase58 failed: %v", err)
+		}
+		if !bytes.Equal(in, out) {
+			t.Fatalf("round trip mismatch: %x != %x", in, out)
+		}
+	}
+
+	if got := solana.EncodeBase58(make([]byte, 32)); got != "11111111111111111111111111111111" {
+		t.Fatalf("unexpected system program encoding %q", got)
+	}
+	if _, err := solana.DecodeBase58("0OIl"); err == nil {
+		t.Fatal("expected error for invalid base58")
+	}
+}
+
+func TestAccountFilterJSON(t *testing.T) {
+	filters := []solana.AccountFilter{
+		solana.DataSizeFilter(165),
+		solana.MemcmpPubkey(32, tokenProgramID),
+		solana.MemcmpU64(0, 1),
+		solana.TokenAccountStateFilter(),
+	}
+	got, err := json.Marshal(filters)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	want := `[{"dataSize":165},` +
+		`{"memcmp":{"offset":32,"bytes":"` + tokenProgramID + `","encoding":"base58"}},` +
+		`{"memcmp":{"offset":0,"bytes":"Ahg1opVcGX","encoding":"base58"}},` +
+		`"tokenAccountState"]`
+	if string(got) != want {
+		t.Fatalf("unexpected JSON:\n got %s\nwant %s", got, want)
+	}
+
+	var back []solana.AccountFilter
+	if err := json.Unmarshal(got, &back); err != nil {
+		t.Fatalf("unmarshal failed: %v", err)
+	}
+	if !back[3].TokenAccountState || back[1].Memcmp.Bytes != tokenProgramID {
+		t.Fatalf("unexpected round trip: %+v", back)
+	}
+}
+
+func TestMemcmpLimit(t *testing.T) {
+	if err := solana.MemcmpBytes(8, make([]byte, solana.MaxMemcmpBytes)).Validate(); err != nil {
+		t.Fatalf("Validate failed at the limit: %v", err)
+	}
+	long := make([]byte, solana.MaxMemcmpBytes+1)
+	for _, f := range []solana.AccountFilter{
+		solana.MemcmpBytes(8, long),
+		{Memcmp: &solana.MemcmpFilter{Offset: 8, Bytes: base64.StdEncoding.EncodeToString(long), Encoding: solana.EncodingBase64}},
+	} {
+		if err := f.Validate(); err == nil {
+			t.Fatalf("expected a limit error for %s encoding", f.Memcmp.Encoding)
+		}
+	}
+}
+
+func TestDataSizeFilterZero(t *testing.T) {
+	f := solana.DataSizeFilter(0)
+	if err := f.Validate(); err != nil {
+		t.Fatalf("Validate failed: %v", err)
+	}
+	got, err := json.Marshal(f)
+	if err != nil {
+		t.Fatalf("marshal failed: %v", err)
+	}
+	if string(got) != `{"dataSize":0}` {
+		t.Fatalf("unexpected JSON %s", got)
+	}
+}
+
+func TestGetProgramAccountsFilterValidation(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		t.Error("invalid request reached the server")
+	}))
+	defer server.Close()
+	client := solana.NewClient(server.URL)
+
+	cases := map[string][]solana.AccountFilter{
+		"too many filters": {
+			solana.DataSizeFilter(1), solana.DataSizeFilter(2), solana.DataSizeFilter(3),
+			solana.DataSizeFilter(4), solana.DataSizeFilter(5),
+		},
+		"empty filter":   {{}},
+		"bad pubkey":     {solana.MemcmpPubkey(0, "not-base58!")},
+		"base58 too big": {{Memcmp: &solana.MemcmpFilter{Bytes: solana.EncodeBase58(make([]byte, 200))}}},
+	}
+	for name, filters := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := client.GetProgramAccounts(context.Background(), tokenProgramID, &solana.GetProgramAccountsConfig{Filters: filters})
+			if err == nil || !strings.Contains(err.Error(), "invalid getProgramAccounts params") {
+				t.Fatalf("expected validation error, got %v", err)
+			}
+		})
+	}
+}
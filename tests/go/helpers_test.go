@@ -0,0 +1,43 @@
+package solana_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// rpcCall is a JSON-RPC request as received by a fake server.
+type rpcCall struct {
+	ID     uint64            `json:"id"`
+	Method string            `json:"method"`
+	Params []json.RawMessage `json:"params"`
+}
+
+// newRPCServer starts a fake JSON-RPC endpoint that answers every request with
+// the result returned by handle, or with its error when one is returned.
+func newRPCServer(t *testing.T, handle func(call rpcCall) (interface{}, *rpcErrorBody)) *httptest.Server {
+	t.Helper()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var call rpcCall
+		if err := json.NewDecoder(r.Body).Decode(&call); err != nil {
+			t.Errorf("decode request: %v", err)
+			return
+		}
+		result, rpcErr := handle(call)
+		resp := map[string]interface{}{"jsonrpc": "2.0", "id": call.ID}
+		if rpcErr != nil {
+			resp["error"] = rpcErr
+		} else {
+			resp["result"] = result
+		}
+		json.NewEncoder(w).Encode(resp)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+type rpcErrorBody struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
@@ -0,0 +1,82 @@
+package solana_test
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"reflect"
+	"testing"
+
+	solana "github.com/solana-rpc/client"
+)
+
+func TestInterceptorChain(t *testing.T) {
+	var authHeader string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		authHeader = r.Header.Get("Authorization")
+		var call rpcCall
+		json.NewDecoder(r.Body).Decode(&call)
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": call.ID, "result": 99})
+	}))
+	defer server.Close()
+
+	var order []string
+	record := func(name string) solana.Interceptor {
+		return func(ctx context.Context, req *solana.Request, next solana.Invoker) (*solana.RawResponse, error) {
+			order = append(order, name+" "+req.Method)
+			return next(ctx, req)
+		}
+	}
+	auth := func(ctx context.Context, req *solana.Request, next solana.Invoker) (*solana.RawResponse, error) {
+		req.Header.Set("Authorization", "Bearer token")
+		return next(ctx, req)
+	}
+	cached := func(ctx context.Context, req *solana.Request, next solana.Invoker) (*solana.RawResponse, error) {
+		if req.Method == "getGenesisHash" {
+			return &solana.RawResponse{ID: req.ID, Result: json.RawMessage(`"cached"`)}, nil
+		}
+		return next(ctx, req)
+	}
+
+	client := solana.NewClient(server.URL, solana.WithInterceptors(record("outer"), auth, cached, record("inner")))
+	ctx := context.Background()
+
+	slot, err := client.GetSlot(ctx, nil)
+	if err != nil || slot != 99 {
+		t.Fatalf("GetSlot: got %d, %v", slot, err)
+	}
+	if authHeader != "Bearer token" {
+		t.Fatalf("expected auth header to reach the server, got %q", authHeader)
+	}
+
+	hash, err := client.GetGenesisHash(ctx)
+	if err != nil || hash != "cached" {
+		t.Fatalf("GetGenesisHash: got %q, %v", hash, err)
+	}
+
+	want := []string{"outer getSlot", "inner getSlot", "outer getGenesisHash"}
+	if !reflect.DeepEqual(order, want) {
+		t.Fatalf("unexpected interceptor order %v, want %v", order, want)
+	}
+}
+
+func TestInterceptorSeesRPCErrors(t *testing.T) {
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		return nil, &rpcErrorBody{Code: -32602, Message: "Invalid params"}
+	})
+
+	var seen *solana.RPCError
+	observe := func(ctx context.Context, req *solana.Request, next solana.Invoker) (*solana.RawResponse, error) {
+		resp, err := next(ctx, req)
+		if err == nil {
+			seen = resp.Error
+		}
+		return resp, err
+	}
+
+	_, err := solana.NewClient(server.URL, solana.WithInterceptors(observe)).GetBalance(context.Background(), "x", nil)
+	if err == nil || seen == nil || seen.Code != -32602 {
+		t.Fatalf("expected interceptor to see RPC error, got err=%v seen=%v", err, seen)
+	}
+}
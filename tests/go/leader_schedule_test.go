@@ -0,0 +1,125 @@
+package solana_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"reflect"
+	"testing"
+
+	solana "github.com/solana-rpc/client"
+)
+
+// newLeaderServer serves an 8-slot epoch schedule without warmup, a current
+// slot of 21 (epoch 2, index 5) and leader schedules for epochs 2 and 3 only.
+func newLeaderServer(t *testing.T) *solana.Client {
+	schedules := map[int64]map[string][]int{
+		16: {"A": {0, 1, 2, 3}, "B": {4, 5, 6, 7}},
+		24: {"B": {0, 1}, "A": {2, 3, 4, 5, 6, 7}},
+	}
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		switch call.Method {
+		case "getEpochSchedule":
+			return map[string]interface{}{"slotsPerEpoch": 8, "leaderScheduleSlotOffset": 8}, nil
+		case "getEpochInfo":
+			return map[string]interface{}{"epoch": 2, "slotIndex": 5, "slotsInEpoch": 8, "absoluteSlot": 21}, nil
+		case "getLeaderSchedule":
+			var slot int64
+			json.Unmarshal(call.Params[0], &slot)
+			if s, ok := schedules[slot]; ok {
+				return s, nil
+			}
+			return nil, nil
+		case "getSlotLeaders":
+			var limit int
+			json.Unmarshal(call.Params[1], &limit)
+			leaders := make([]string, limit)
+			for i := range leaders {
+				leaders[i] = "C"
+			}
+			return leaders, nil
+		}
+		return nil, &rpcErrorBody{Code: -32601, Message: "method not found"}
+	})
+	return solana.NewClient(server.URL)
+}
+
+func TestLeaderScheduleCacheLeaderAt(t *testing.T) {
+	cache := solana.NewLeaderScheduleCache(newLeaderServer(t))
+	ctx := context.Background()
+
+	for slot, want := range map[solana.Slot]solana.Pubkey{16: "A", 21: "B", 24: "B", 26: "A"} {
+		got, err := cache.LeaderAt(ctx, slot)
+		if err != nil || got != want {
+			t.Errorf("LeaderAt(%d) = %q, %v; want %q", slot, got, err, want)
+		}
+	}
+	if _, err := cache.LeaderAt(ctx, 40); !errors.Is(err, solana.ErrLeaderScheduleUnavailable) {
+		t.Fatalf("expected ErrLeaderScheduleUnavailable, got %v", err)
+	}
+}
+
+func TestLeaderScheduleCacheNextSlotsFor(t *testing.T) {
+	cache := solana.NewLeaderScheduleCache(newLeaderServer(t))
+
+	got, err := cache.NextSlotsFor(context.Background(), "A", 3)
+	if err != nil {
+		t.Fatalf("NextSlotsFor failed: %v", err)
+	}
+	if want := []solana.Slot{26, 27, 28}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("NextSlotsFor(A) = %v, want %v", got, want)
+	}
+
+	// Only the known schedules are searched.
+	got, err = cache.NextSlotsFor(context.Background(), "B", 10)
+	if err != nil {
+		t.Fatalf("NextSlotsFor failed: %v", err)
+	}
+	if want := []solana.Slot{21, 22, 23, 24, 25}; !reflect.DeepEqual(got, want) {
+		t.Fatalf("NextSlotsFor(B) = %v, want %v", got, want)
+	}
+}
+
+func TestLeaderScheduleCacheUpcomingLeaders(t *testing.T) {
+	cache := solana.NewLeaderScheduleCache(newLeaderServer(t))
+
+	got, err := cache.UpcomingLeaders(context.Background(), 13)
+	if err != nil {
+		t.Fatalf("UpcomingLeaders failed: %v", err)
+	}
+	if len(got) != 13 {
+		t.Fatalf("got %d leaders, want 13", len(got))
+	}
+	if got[0] != (solana.SlotLeader{Slot: 21, Leader: "B"}) || got[5] != (solana.SlotLeader{Slot: 26, Leader: "A"}) {
+		t.Fatalf("unexpected leaders %v", got)
+	}
+	// Slots past epoch 3 come from getSlotLeaders.
+	if got[11] != (solana.SlotLeader{Slot: 32, Leader: "C"}) || got[12].Slot != 33 {
+		t.Fatalf("unexpected fallback leaders %v", got[11:])
+	}
+}
+
+func TestLeaderScheduleCacheWithMockRPC(t *testing.T) {
+	mock := &solana.MockRPC{
+		GetEpochScheduleFunc: func(context.Context) (solana.EpochSchedule, error) {
+			return solana.EpochSchedule{SlotsPerEpoch: 4, LeaderScheduleSlotOffset: 4}, nil
+		},
+		GetEpochInfoFunc: func(context.Context, *solana.CommitmentConfig) (solana.EpochInfo, error) {
+			return solana.EpochInfo{Epoch: 1, SlotIndex: 1, SlotsInEpoch: 4, AbsoluteSlot: 5}, nil
+		},
+		GetLeaderScheduleFunc: func(ctx context.Context, slot *solana.Slot, config *solana.GetLeaderScheduleConfig) (solana.LeaderSchedule, error) {
+			return solana.LeaderSchedule{"A": {0, 1}, "B": {2, 3}}, nil
+		},
+	}
+	cache := solana.NewLeaderScheduleCache(mock)
+	if got, err := cache.LeaderAt(context.Background(), 6); err != nil || got != "B" {
+		t.Fatalf("LeaderAt(6) = %q, %v; want B", got, err)
+	}
+
+	mock.GetEpochScheduleFunc = func(context.Context) (solana.EpochSchedule, error) {
+		return solana.EpochSchedule{}, nil
+	}
+	if _, err := solana.NewLeaderScheduleCache(mock).LeaderAt(context.Background(), 6); err == nil {
+		t.Fatal("expected an error for a schedule without slots per epoch")
+	}
+}
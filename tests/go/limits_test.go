@@ -0,0 +1,59 @@
+package solana_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	solana "github.com/solana-rpc/client"
+)
+
+func TestResponseSizeLimit(t *testing.T) {
+	big := strings.Repeat("a", 4096)
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		return big, nil
+	})
+	ctx := context.Background()
+
+	client := solana.NewClient(server.URL, solana.WithDefaultMaxResponseBytes(1024))
+	_, err := client.GetGenesisHash(ctx)
+	if !errors.Is(err, solana.ErrResponseTooLarge) {
+		t.Fatalf("expected ErrResponseTooLarge, got %v", err)
+	}
+	var tooLarge *solana.ResponseTooLargeError
+	if !errors.As(err, &tooLarge) || tooLarge.Limit != 1024 {
+		t.Fatalf("unexpected error details: %v", err)
+	}
+
+	hash, err := client.GetGenesisHash(solana.WithMaxResponseBytes(ctx, 8192))
+	if err != nil || hash != big {
+		t.Fatalf("expected per-call limit to allow the response, got %v", err)
+	}
+}
+
+func TestBodyReadTimeout(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,`)
+		w.(http.Flusher).Flush()
+		select {
+		case <-r.Context().Done():
+		case <-time.After(5 * time.Second):
+		}
+	}))
+	defer server.Close()
+
+	client := solana.NewClient(server.URL, solana.WithBodyReadTimeout(50*time.Millisecond))
+	start := time.Now()
+	_, err := client.GetSlot(context.Background(), nil)
+	if !errors.Is(err, solana.ErrBodyReadTimeout) {
+		t.Fatalf("expected ErrBodyReadTimeout, got %v", err)
+	}
+	if time.Since(start) > 2*time.Second {
+		t.Fatalf("body read was not cut short")
+	}
+}
@@ -0,0 +1,49 @@
+package solana_test
+
+import (
+	"bytes"
+	"context"
+	"log/slog"
+	"strings"
+	"testing"
+
+	solana "github.com/solana-rpc/client"
+)
+
+func TestLoggerRedactsEndpointAndTruncatesBodies(t *testing.T) {
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		return strings.Repeat("x", 100), nil
+	})
+
+	var buf bytes.Buffer
+	logger := slog.New(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	endpoint := server.URL + "/0123456789abcdef0123/?api-key=supersecret"
+	client := solana.NewClient(endpoint, solana.WithLogger(logger), solana.WithLogBodies(20))
+
+	if _, err := client.GetGenesisHash(context.Background()); err != nil {
+		t.Fatalf("GetGenesisHash failed: %v", err)
+	}
+
+	out := buf.String()
+	for _, want := range []string{"method=getGenesisHash", "request_id=1", "REDACTED", "response_bytes=", "bytes total"} {
+		if !strings.Contains(out, want) {
+			t.Errorf("log output missing %q:\n%s", want, out)
+		}
+	}
+	for _, secret := range []string{"supersecret", "0123456789abcdef0123"} {
+		if strings.Contains(out, secret) {
+			t.Errorf("log output leaks %q:\n%s", secret, out)
+		}
+	}
+}
+
+func TestTransportErrorsAreRedacted(t *testing.T) {
+	client := solana.NewClient("http://127.0.0.1:1/?api-key=supersecret")
+	_, err := client.GetSlot(context.Background(), nil)
+	if err == nil {
+		t.Fatal("expected connection error")
+	}
+	if strings.Contains(err.Error(), "supersecret") {
+		t.Fatalf("error leaks API key: %v", err)
+	}
+}
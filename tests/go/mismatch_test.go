@@ -0,0 +1,93 @@
+package solana_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	solana "github.com/solana-rpc/client"
+)
+
+func TestResponseMismatch(t *testing.T) {
+	cases := map[string]string{
+		"wrong id":      `{"jsonrpc":"2.0","id":12345,"result":1}`,
+		"missing id":    `{"jsonrpc":"2.0","result":1}`,
+		"wrong version": `{"jsonrpc":"1.0","id":1,"result":1}`,
+	}
+	for name, body := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprint(w, body)
+			}))
+			defer server.Close()
+
+			_, err := solana.NewClient(server.URL).GetSlot(context.Background(), nil)
+			if !errors.Is(err, solana.ErrResponseMismatch) {
+				t.Fatalf("expected ErrResponseMismatch, got %v", err)
+			}
+			var mismatch *solana.ResponseMismatchError
+			if !errors.As(err, &mismatch) || mismatch.WantID != 1 || mismatch.Method != "getSlot" {
+				t.Fatalf("unexpected mismatch details: %+v", mismatch)
+			}
+		})
+	}
+}
+
+func TestParseErrorWithNullIDIsSurfaced(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":null,"error":{"code":-32700,"message":"Parse error"}}`)
+	}))
+	defer server.Close()
+
+	_, err := solana.NewClient(server.URL).GetSlot(context.Background(), nil)
+	var rpcErr *solana.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32700 {
+		t.Fatalf("expected parse error to be returned, got %v", err)
+	}
+}
+
+func TestHTTPStatusError(t *testing.T) {
+	cases := map[string]struct {
+		status int
+		body   string
+	}{
+		"text":    {http.StatusServiceUnavailable, "upstream unavailable\n"},
+		"not rpc": {http.StatusTooManyRequests, `{"message":"rate limited"}`},
+		"empty":   {http.StatusBadGateway, ""},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.WriteHeader(tc.status)
+				fmt.Fprint(w, tc.body)
+			}))
+			defer server.Close()
+
+			_, err := solana.NewClient(server.URL).GetSlot(context.Background(), nil)
+			if !errors.Is(err, solana.ErrHTTPStatus) {
+				t.Fatalf("expected ErrHTTPStatus, got %v", err)
+			}
+			var statusErr *solana.HTTPStatusError
+			if !errors.As(err, &statusErr) || statusErr.StatusCode != tc.status || statusErr.Method != "getSlot" {
+				t.Fatalf("unexpected status error details: %+v", statusErr)
+			}
+		})
+	}
+}
+
+func TestRPCErrorWithFailedStatusIsSurfaced(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusTooManyRequests)
+		fmt.Fprint(w, `{"jsonrpc":"2.0","id":1,"error":{"code":-32005,"message":"rate limited"}}`)
+	}))
+	defer server.Close()
+
+	_, err := solana.NewClient(server.URL).GetSlot(context.Background(), nil)
+	var rpcErr *solana.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32005 {
+		t.Fatalf("expected the RPC error to be returned, got %v", err)
+	}
+}
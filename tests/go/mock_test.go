@@ -0,0 +1,45 @@
+package solana_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	solana "github.com/solana-rpc/client"
+)
+
+// lamportsOf is the kind of downstream code that should accept solana.RPC.
+func lamportsOf(ctx context.Context, rpc solana.RPC, pubkey solana.Pubkey) (int64, error) {
+	resp, err := rpc.GetBalance(ctx, pubkey, nil)
+	return resp.Value, err
+}
+
+func TestMockRPC(t *testing.T) {
+	mock := &solana.MockRPC{
+		GetBalanceFunc: func(ctx context.Context, pubkey solana.Pubkey, config *solana.CommitmentConfig) (solana.RpcResponseU64, error) {
+			return solana.RpcResponseU64{Value: 42}, nil
+		},
+	}
+
+	got, err := lamportsOf(context.Background(), mock, "owner")
+	if err != nil || got != 42 {
+		t.Fatalf("expected 42, got %d (%v)", got, err)
+	}
+
+	if _, err := mock.GetSlot(context.Background(), nil); !errors.Is(err, solana.ErrNotMocked) {
+		t.Fatalf("expected ErrNotMocked, got %v", err)
+	}
+
+	calls := mock.CallsTo("GetBalance")
+	if len(calls) != 1 || calls[0].Args[0] != "owner" {
+		t.Fatalf("unexpected recorded calls: %+v", calls)
+	}
+	if len(mock.Calls()) != 2 {
+		t.Fatalf("expected 2 recorded calls, got %d", len(mock.Calls()))
+	}
+}
+
+func TestClientImplementsRPC(t *testing.T) {
+	var _ solana.RPC = solana.NewClient(solana.Devnet)
+	var _ solana.RPC = solana.NewSession(solana.NewClient(solana.Devnet))
+}
@@ -0,0 +1,71 @@
+package solana_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"sync/atomic"
+	"testing"
+
+	solana "github.com/solana-rpc/client"
+)
+
+func TestGetMultipleAccountsChunked(t *testing.T) {
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		atomic.AddInt32(&requests, 1)
+		var req struct {
+			ID     uint64            `json:"id"`
+			Params []json.RawMessage `json:"params"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			t.Errorf("decode request: %v", err)
+			return
+		}
+		var keys []string
+		json.Unmarshal(req.Params[0], &keys)
+		if len(keys) > solana.MaxMultipleAccounts {
+			t.Errorf("chunk of %d keys exceeds limit", len(keys))
+		}
+
+		values := make([]solana.AccountInfo, len(keys))
+		for i, k := range keys {
+			n, _ := strconv.Atoi(k)
+			values[i] = solana.AccountInfo{Lamports: int64(n)}
+		}
+		result, _ := json.Marshal(solana.MultipleAccountsResponse{
+			Context: solana.RpcContext{Slot: 1000},
+			Value:   values,
+		})
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","id":%d,"result":%s}`, req.ID, result)
+	}))
+	defer server.Close()
+
+	keys := make([]solana.Pubkey, 250)
+	for i := range keys {
+		keys[i] = strconv.Itoa(i)
+	}
+
+	client := solana.NewClient(server.URL)
+	resp, err := client.GetMultipleAccountsChunked(context.Background(), keys, nil, 2)
+	if err != nil {
+		t.Fatalf("GetMultipleAccountsChunked failed: %v", err)
+	}
+	if got := atomic.LoadInt32(&requests); got != 3 {
+		t.Fatalf("expected 3 requests, got %d", got)
+	}
+	if len(resp.Value) != len(keys) {
+		t.Fatalf("expected %d accounts, got %d", len(keys), len(resp.Value))
+	}
+	for i, acc := range resp.Value {
+		if acc.Lamports != int64(i) {
+			t.Fatalf("account %d out of order: lamports %d", i, acc.Lamports)
+		}
+	}
+	if resp.Context.Slot != 1000 {
+		t.Fatalf("expected context slot 1000, got %d", resp.Context.Slot)
+	}
+}
@@ -0,0 +1,83 @@
+package solana_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"testing"
+
+	solana "github.com/solana-rpc/client"
+)
+
+func newPrioritizationFeeServer(t *testing.T, gotAccounts *[]string) *solana.Client {
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		if len(call.Params) > 0 {
+			json.Unmarshal(call.Params[0], gotAccounts)
+		}
+		// Slots 101..120 paid fees 10, 20, ..., 200; the oldest slot spiked.
+		fees := []map[string]interface{}{{"slot": 100, "prioritizationFee": 1000000}}
+		for i := 1; i <= 20; i++ {
+			fees = append(fees, map[string]interface{}{"slot": 100 + i, "prioritizationFee": 10 * i})
+		}
+		return fees, nil
+	})
+	return solana.NewClient(server.URL)
+}
+
+func TestPriorityFeeEstimate(t *testing.T) {
+	var accounts []string
+	estimator := solana.NewPriorityFeeEstimator(newPrioritizationFeeServer(t, &accounts))
+	estimator.Window = 20
+
+	est, err := estimator.Estimate(context.Background(), []solana.Pubkey{tokenProgramID})
+	if err != nil {
+		t.Fatalf("Estimate failed: %v", err)
+	}
+	if len(accounts) != 1 || accounts[0] != tokenProgramID {
+		t.Fatalf("unexpected accounts sent: %v", accounts)
+	}
+	want := solana.PriorityFeeEstimate{Min: 10, Low: 50, Medium: 100, High: 150, P95: 190, Samples: 20}
+	if est != want {
+		t.Fatalf("Estimate = %+v, want %+v", est, want)
+	}
+
+	estimator.Window = 0
+	estimator.Cap = 120
+	est, err = estimator.Estimate(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Estimate failed: %v", err)
+	}
+	if est.Samples != 21 || est.High != 120 || est.P95 != 120 || est.Medium != 110 {
+		t.Fatalf("unexpected capped estimate %+v", est)
+	}
+}
+
+func TestPriorityFeeEstimateWithMockRPC(t *testing.T) {
+	mock := &solana.MockRPC{
+		GetRecentPrioritizationFeesFunc: func(ctx context.Context, addresses *[]solana.Pubkey) ([]solana.PrioritizationFee, error) {
+			return []solana.PrioritizationFee{{Slot: 1, PrioritizationFee: 7}, {Slot: 2, PrioritizationFee: 7}}, nil
+		},
+	}
+	est, err := solana.NewPriorityFeeEstimator(mock).Estimate(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("Estimate failed: %v", err)
+	}
+	if est.Samples != 2 || est.Medium != 7 {
+		t.Fatalf("unexpected estimate %+v", est)
+	}
+}
+
+func TestPriorityFeeInstruction(t *testing.T) {
+	est := solana.PriorityFeeEstimate{Medium: 0x0102}
+	ix := est.SetComputeUnitPrice(solana.PriorityMedium)
+	if ix.ProgramID != solana.ComputeBudgetProgramID || len(ix.Accounts) != 0 {
+		t.Fatalf("unexpected instruction %+v", ix)
+	}
+	if want := []byte{3, 0x02, 0x01, 0, 0, 0, 0, 0, 0}; !bytes.Equal(ix.Data, want) {
+		t.Fatalf("data = %x, want %x", ix.Data, want)
+	}
+
+	if got := solana.SetComputeUnitLimit(200000).Data; !bytes.Equal(got, []byte{2, 0x40, 0x0d, 0x03, 0}) {
+		t.Fatalf("SetComputeUnitLimit data = %x", got)
+	}
+}
@@ -0,0 +1,83 @@
+package solana_test
+
+import (
+	"testing"
+
+	solana "github.com/solana-rpc/client"
+)
+
+func TestParseProgramLogs(t *testing.T) {
+	logs := []string{
+		"Program ComputeBudget111111111111111111111111111111 invoke [1]",
+		"Program ComputeBudget111111111111111111111111111111 success",
+		"Program Swap111 invoke [1]",
+		"Program log: Instruction: Swap",
+		"Program Token111 invoke [2]",
+		"Program log: Instruction: Transfer",
+		"Program Token111 consumed 4645 of 180000 compute units",
+		"Program Token111 success",
+		"Program data: aGVsbG8= IHdvcmxk",
+		"Program Token111 invoke [2]",
+		"Program log: Error: insufficient funds",
+		"Program Token111 consumed 3000 of 170000 compute units",
+		"Program Token111 failed: custom program error: 0x1",
+		"Program Swap111 consumed 30000 of 199850 compute units",
+		"Program return: Swap111 AQI=",
+		"Program Swap111 failed: custom program error: 0x1",
+	}
+	parsed := solana.ParseProgramLogs(logs)
+
+	if parsed.Truncated || len(parsed.Invocations) != 2 {
+		t.Fatalf("unexpected top level %+v", parsed)
+	}
+	if !parsed.Invocations[0].Succeeded {
+		t.Fatal("expected the compute budget instruction to succeed")
+	}
+
+	swap := parsed.Invocations[1]
+	if !swap.Failed || swap.Err != "custom program error: 0x1" || swap.ComputeUnitsConsumed != 30000 || swap.ComputeUnitsLimit != 199850 {
+		t.Fatalf("unexpected swap invocation %+v", swap)
+	}
+	if len(swap.Logs) != 1 || swap.Logs[0] != "Instruction: Swap" {
+		t.Fatalf("unexpected swap logs %q", swap.Logs)
+	}
+	if len(swap.Data) != 1 || string(swap.Data[0]) != "hello world" {
+		t.Fatalf("unexpected swap data %q", swap.Data)
+	}
+	if string(swap.ReturnData) != "\x01\x02" {
+		t.Fatalf("unexpected return data %x", swap.ReturnData)
+	}
+	if len(swap.Invocations) != 2 || swap.Invocations[0].Depth != 2 || !swap.Invocations[0].Succeeded {
+		t.Fatalf("unexpected inner invocations %+v", swap.Invocations)
+	}
+
+	failed := parsed.FailedInvocation()
+	if failed != swap.Invocations[1] || failed.ComputeUnitsConsumed != 3000 || failed.Logs[0] != "Error: insufficient funds" {
+		t.Fatalf("unexpected failed invocation %+v", failed)
+	}
+
+	var count int
+	parsed.Walk(func(*solana.ProgramInvocation) { count++ })
+	if count != 4 {
+		t.Fatalf("walked %d invocations, want 4", count)
+	}
+}
+
+func TestParseProgramLogsTruncated(t *testing.T) {
+	parsed := solana.ParseProgramLogs([]string{
+		"Program Swap111 invoke [1]",
+		"Program log: start",
+		"Program Token111 invoke [2]",
+		"Log truncated",
+	})
+	if !parsed.Truncated {
+		t.Fatal("expected the log to be marked truncated")
+	}
+	swap := parsed.Invocations[0]
+	if swap.Succeeded || swap.Failed || len(swap.Invocations) != 1 {
+		t.Fatalf("unexpected unfinished invocation %+v", swap)
+	}
+	if parsed.FailedInvocation() != nil {
+		t.Fatal("expected no failed invocation")
+	}
+}
@@ -0,0 +1,116 @@
+package solana_test
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	solana "github.com/solana-rpc/client"
+)
+
+func TestRateLimitDelaysCalls(t *testing.T) {
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		return 1, nil
+	})
+	client := solana.NewClient(server.URL, solana.WithRateLimit(20, 1))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetSlot(context.Background(), nil); err != nil {
+			t.Fatalf("GetSlot failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("3 calls at 20 rps took only %v", elapsed)
+	}
+}
+
+func TestRateLimitMethodWeight(t *testing.T) {
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		return []interface{}{}, nil
+	})
+	client := solana.NewClient(server.URL,
+		solana.WithRateLimit(100, 10),
+		solana.WithMethodWeight("getProgramAccounts", 10))
+
+	start := time.Now()
+	for i := 0; i < 2; i++ {
+		if _, err := client.GetProgramAccounts(context.Background(), tokenProgramID, nil); err != nil {
+			t.Fatalf("GetProgramAccounts failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("second weighted call was not delayed: %v", elapsed)
+	}
+}
+
+func TestRateLimitZeroWeightStillCounts(t *testing.T) {
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		return 1, nil
+	})
+	client := solana.NewClient(server.URL,
+		solana.WithRateLimit(20, 1),
+		solana.WithMethodWeight("getSlot", 0))
+
+	start := time.Now()
+	for i := 0; i < 3; i++ {
+		if _, err := client.GetSlot(context.Background(), nil); err != nil {
+			t.Fatalf("GetSlot failed: %v", err)
+		}
+	}
+	if elapsed := time.Since(start); elapsed < 90*time.Millisecond {
+		t.Fatalf("zero-weight calls bypassed the limit: 3 calls took %v", elapsed)
+	}
+}
+
+func TestRateLimitRespectsContext(t *testing.T) {
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		return 1, nil
+	})
+	client := solana.NewClient(server.URL, solana.WithRateLimit(0.1, 1))
+	if _, err := client.GetSlot(context.Background(), nil); err != nil {
+		t.Fatalf("GetSlot failed: %v", err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	if _, err := client.GetSlot(ctx, nil); !errors.Is(err, context.DeadlineExceeded) {
+		t.Fatalf("expected deadline exceeded, got %v", err)
+	}
+}
+
+func TestMaxInFlight(t *testing.T) {
+	var current, peak int32
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		n := atomic.AddInt32(&current, 1)
+		for {
+			p := atomic.LoadInt32(&peak)
+			if n <= p || atomic.CompareAndSwapInt32(&peak, p, n) {
+				break
+			}
+		}
+		time.Sleep(20 * time.Millisecond)
+		atomic.AddInt32(&current, -1)
+		return 1, nil
+	})
+	client := solana.NewClient(server.URL, solana.WithMaxInFlight(2))
+
+	var wg sync.WaitGroup
+	for i := 0; i < 8; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			if _, err := client.GetSlot(context.Background(), nil); err != nil {
+				t.Errorf("GetSlot failed: %v", err)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if peak > 2 {
+		t.Fatalf("saw %d concurrent requests, want at most 2", peak)
+	}
+}
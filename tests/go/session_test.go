@@ -0,0 +1,61 @@
+package solana_test
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	solana "github.com/solana-rpc/client"
+)
+
+func TestSessionSetsMinContextSlot(t *testing.T) {
+	var minSlots []int64
+	rejected := false
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		var cfg solana.CommitmentConfig
+		if len(call.Params) > 1 {
+			json.Unmarshal(call.Params[1], &cfg)
+		}
+		minSlots = append(minSlots, cfg.MinContextSlot)
+
+		if cfg.MinContextSlot == 100 && !rejected {
+			rejected = true
+			return nil, &rpcErrorBody{Code: solana.ErrCodeMinContextSlotNotReached, Message: "Minimum context slot has not been reached"}
+		}
+		return solana.RpcResponseU64{Context: solana.RpcContext{Slot: 100}, Value: 5}, nil
+	})
+
+	session := solana.NewSession(solana.NewClient(server.URL))
+	session.RetryBackoff = time.Millisecond
+	ctx := context.Background()
+
+	if _, err := session.GetBalance(ctx, "a", nil); err != nil {
+		t.Fatalf("first GetBalance failed: %v", err)
+	}
+	if session.Slot() != 100 {
+		t.Fatalf("expected session slot 100, got %d", session.Slot())
+	}
+	if _, err := session.GetBalance(ctx, "a", nil); err != nil {
+		t.Fatalf("second GetBalance failed: %v", err)
+	}
+
+	want := []int64{0, 100, 100}
+	if len(minSlots) != len(want) {
+		t.Fatalf("expected %d requests, got %v", len(want), minSlots)
+	}
+	for i := range want {
+		if minSlots[i] != want[i] {
+			t.Fatalf("request %d: expected minContextSlot %d, got %d", i, want[i], minSlots[i])
+		}
+	}
+}
+
+func TestSessionObserveIsMonotonic(t *testing.T) {
+	session := solana.NewSession(solana.NewClient(solana.Devnet))
+	session.Observe(50)
+	session.Observe(20)
+	if session.Slot() != 50 {
+		t.Fatalf("expected slot 50, got %d", session.Slot())
+	}
+}
@@ -0,0 +1,171 @@
+package solana_test
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	solana "github.com/solana-rpc/client"
+)
+
+func TestSimulateTransactionTypedResult(t *testing.T) {
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		var config map[string]interface{}
+		json.Unmarshal(call.Params[1], &config)
+		accounts, _ := config["accounts"].(map[string]interface{})
+		if accounts["encoding"] != "base64" || len(accounts["addresses"].([]interface{})) != 2 {
+			t.Errorf("unexpected accounts config %v", config["accounts"])
+		}
+
+		return map[string]interface{}{
+			"context": map[string]interface{}{"slot": 7},
+			"value": map[string]interface{}{
+				"err":  map[string]interface{}{"InstructionError": []interface{}{1, map[string]interface{}{"Custom": 6001}}},
+				"logs": []string{"Program 11111111111111111111111111111111 invoke [1]"},
+				"accounts": []interface{}{
+					map[string]interface{}{"lamports": 10, "owner": tokenProgramID, "data": []string{"AQID", "base64"}},
+					nil,
+				},
+				"unitsConsumed": 2500,
+				"returnData":    map[string]interface{}{"programId": tokenProgramID, "data": []string{"aGk=", "base64"}},
+				"innerInstructions": []interface{}{
+					map[string]interface{}{"index": 0, "instructions": []interface{}{
+						// A program the node has no parser for, then one it has.
+						map[string]interface{}{"programId": "prog", "accounts": []string{"a", "b"}, "data": "3Bxs", "stackHeight": 2},
+						map[string]interface{}{
+							"programId": tokenProgramID, "program": "spl-token", "stackHeight": 2,
+							"parsed": map[string]interface{}{"type": "transfer", "info": map[string]interface{}{"amount": "1"}},
+						},
+					}},
+				},
+			},
+		}, nil
+	})
+	client := solana.NewClient(server.URL)
+
+	resp, err := client.SimulateTransaction(context.Background(), "AQ==", &solana.SimulateTransactionConfig{
+		Accounts: &solana.SimulateAccountsConfig{
+			Addresses: []solana.Pubkey{tokenProgramID, tokenProgramID},
+			Encoding:  solana.EncodingBase64,
+		},
+	})
+	if err != nil {
+		t.Fatalf("SimulateTransaction failed: %v", err)
+	}
+	result := resp.Value
+
+	if result.Err == nil || result.Err.Kind != "InstructionError" || result.Err.InstructionIndex != 1 ||
+		result.Err.Instruction.Kind != "Custom" || result.Err.Instruction.Custom != 6001 {
+		t.Fatalf("unexpected error %+v", result.Err)
+	}
+	if got := result.Err.Error(); got != "instruction 1 failed: custom program error: 0x1771" {
+		t.Fatalf("unexpected error text %q", got)
+	}
+	if result.UnitsConsumed != 2500 || len(result.Logs) != 1 {
+		t.Fatalf("unexpected result %+v", result)
+	}
+	if len(result.Accounts) != 2 || result.Accounts[1] != nil || string(result.Accounts[0].Data.Bytes) != "\x01\x02\x03" {
+		t.Fatalf("unexpected accounts %+v", result.Accounts)
+	}
+	if result.ReturnData == nil || string(result.ReturnData.Data) != "hi" || result.ReturnData.ProgramID != tokenProgramID {
+		t.Fatalf("unexpected return data %+v", result.ReturnData)
+	}
+	inner := result.InnerInstructions
+	if len(inner) != 1 || len(inner[0].Instructions) != 2 {
+		t.Fatalf("unexpected inner instructions %+v", inner)
+	}
+	partial, parsed := inner[0].Instructions[0], inner[0].Instructions[1]
+	if partial.ProgramID != "prog" || len(partial.Accounts) != 2 || partial.Accounts[1] != "b" || partial.Data != "3Bxs" || *partial.StackHeight != 2 {
+		t.Fatalf("unexpected partially decoded instruction %+v", partial)
+	}
+	if parsed.Program != "spl-token" || parsed.ProgramID != tokenProgramID || !strings.Contains(string(parsed.Parsed), `"transfer"`) {
+		t.Fatalf("unexpected parsed instruction %+v", parsed)
+	}
+}
+
+func TestTransactionErrorForms(t *testing.T) {
+	cases := map[string]string{
+		`"AccountNotFound"`: "AccountNotFound",
+		`{"InsufficientFundsForRent":{"account_index":2}}`: "InsufficientFundsForRent",
+		`{"InstructionError":[0,"InvalidAccountData"]}`:    "instruction 0 failed: InvalidAccountData",
+	}
+	for in, want := range cases {
+		var e solana.TransactionError
+		if err := json.Unmarshal([]byte(in), &e); err != nil {
+			t.Fatalf("unmarshal %s: %v", in, err)
+		}
+		if e.Error() != want {
+			t.Errorf("%s: got %q, want %q", in, e.Error(), want)
+		}
+		out, _ := json.Marshal(e)
+		if string(out) != in {
+			t.Errorf("%s: marshalled back as %s", in, out)
+		}
+	}
+}
+
+func TestAccountDataEncodings(t *testing.T) {
+	cases := map[string]string{
+		`["AQID","base64"]`: "\x01\x02\x03",
+		`["Ldp","base58"]`:  "\x01\x02\x03",
+		`"Ldp"`:             "\x01\x02\x03",
+	}
+	for in, want := range cases {
+		var d solana.AccountData
+		if err := json.Unmarshal([]byte(in), &d); err != nil {
+			t.Fatalf("unmarshal %s: %v", in, err)
+		}
+		if string(d.Bytes) != want {
+			t.Errorf("%s: got %x", in, d.Bytes)
+		}
+	}
+
+	var parsed solana.AccountData
+	if err := json.Unmarshal([]byte(`{"program":"spl-token"}`), &parsed); err != nil || parsed.Encoding != solana.EncodingJsonParsed {
+		t.Fatalf("unexpected jsonParsed data %+v, %v", parsed, err)
+	}
+}
+
+func TestGetTransactionInnerInstructionForms(t *testing.T) {
+	server := newRPCServer(t, func(call rpcCall) (interface{}, *rpcErrorBody) {
+		var config solana.GetTransactionConfig
+		json.Unmarshal(call.Params[1], &config)
+		instruction := map[string]interface{}{"programIdIndex": 2, "accounts": []int{0, 1}, "data": "3Bxs", "stackHeight": 2}
+		if config.Encoding == solana.TransactionEncodingJsonParsed {
+			// A program without a parser comes back partially decoded.
+			instruction = map[string]interface{}{"programId": "prog", "accounts": []string{"a", "b"}, "data": "3Bxs", "stackHeight": 2}
+		}
+		return map[string]interface{}{
+			"slot": 5,
+			"meta": map[string]interface{}{
+				"innerInstructions": []interface{}{
+					map[string]interface{}{"index": 0, "instructions": []interface{}{instruction}},
+				},
+			},
+		}, nil
+	})
+	client := solana.NewClient(server.URL)
+
+	for _, encoding := range []solana.TransactionEncoding{solana.TransactionEncodingJson, solana.TransactionEncodingJsonParsed} {
+		resp, err := client.GetTransaction(context.Background(), "sig", &solana.GetTransactionConfig{Encoding: encoding})
+		if err != nil {
+			t.Fatalf("%s: GetTransaction failed: %v", encoding, err)
+		}
+		inner := resp.Meta.InnerInstructions
+		if len(inner) != 1 || len(inner[0].Instructions) != 1 {
+			t.Fatalf("%s: unexpected inner instructions %+v", encoding, inner)
+		}
+		ix := inner[0].Instructions[0]
+		switch encoding {
+		case solana.TransactionEncodingJson:
+			if ix.Parsed != nil || ix.Compiled == nil || ix.Compiled.ProgramIDIndex != 2 || len(ix.Compiled.Accounts) != 2 {
+				t.Fatalf("unexpected compiled instruction %+v", ix)
+			}
+		default:
+			if ix.Compiled != nil || ix.Parsed == nil || ix.Parsed.ProgramID != "prog" || ix.Parsed.Accounts[0] != "a" {
+				t.Fatalf("unexpected parsed instruction %+v", ix)
+			}
+		}
+	}
+}
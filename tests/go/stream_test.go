@@ -0,0 +1,216 @@
+package solana_test
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	solana "github.com/solana-rpc/client"
+)
+
+func programAccountsServer(result string) *httptest.Server {
+	return httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","result":%s,"id":1}`, result)
+	}))
+}
+
+func accountsJSON(n int) string {
+	items := make([]string, n)
+	for i := range items {
+		items[i] = fmt.Sprintf(`{"pubkey":"key%d","account":{"lamports":%d,"owner":"prog","extra":{"nested":[1,2]}}}`, i, i+1)
+	}
+	return "[" + strings.Join(items, ",") + "]"
+}
+
+func TestGetProgramAccountsStream(t *testing.T) {
+	cases := map[string]string{
+		"plain":       accountsJSON(5),
+		"withContext": `{"context":{"slot":42},"value":` + accountsJSON(5) + `}`,
+	}
+	for name, result := range cases {
+		t.Run(name, func(t *testing.T) {
+			server := programAccountsServer(result)
+			defer server.Close()
+
+			var got []solana.ProgramAccount
+			err := solana.NewClient(server.URL).GetProgramAccountsStream(context.Background(), "prog", nil, func(acc solana.ProgramAccount) error {
+				got = append(got, acc)
+				return nil
+			})
+			if err != nil {
+				t.Fatalf("GetProgramAccountsStream failed: %v", err)
+			}
+			if len(got) != 5 {
+				t.Fatalf("expected 5 accounts, got %d", len(got))
+			}
+			if got[3].Pubkey != "key3" || got[3].Account.Lamports != 4 {
+				t.Fatalf("unexpected account: %+v", got[3])
+			}
+		})
+	}
+}
+
+func TestGetProgramAccountsStreamStopsOnCallbackError(t *testing.T) {
+	server := programAccountsServer(accountsJSON(10))
+	defer server.Close()
+
+	stop := errors.New("stop")
+	seen := 0
+	err := solana.NewClient(server.URL).GetProgramAccountsStream(context.Background(), "prog", nil, func(solana.ProgramAccount) error {
+		seen++
+		if seen == 3 {
+			return stop
+		}
+		return nil
+	})
+	if !errors.Is(err, stop) {
+		t.Fatalf("expected callback error, got %v", err)
+	}
+	if seen != 3 {
+		t.Fatalf("expected 3 callbacks, got %d", seen)
+	}
+}
+
+func TestGetProgramAccountsStreamRPCError(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprint(w, `{"jsonrpc":"2.0","error":{"code":-32010,"message":"excluded from account secondary indexes"},"id":1}`)
+	}))
+	defer server.Close()
+
+	err := solana.NewClient(server.URL).GetProgramAccountsStream(context.Background(), "prog", nil, func(solana.ProgramAccount) error {
+		return nil
+	})
+	var rpcErr *solana.RPCError
+	if !errors.As(err, &rpcErr) || rpcErr.Code != -32010 {
+		t.Fatalf("expected RPC error -32010, got %v", err)
+	}
+}
+
+func TestGetProgramAccountsStreamMismatch(t *testing.T) {
+	for name, tc := range map[string]struct {
+		body      string
+		callbacks bool
+	}{
+		// The id can be checked before any account is handed out.
+		"id first": {`{"jsonrpc":"2.0","id":99,"result":%s}`, false},
+		// Agave's order: accounts stream first and the mismatch is reported
+		// at the end.
+		"id last": {`{"jsonrpc":"2.0","result":%s,"id":99}`, true},
+	} {
+		t.Run(name, func(t *testing.T) {
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				fmt.Fprintf(w, tc.body, accountsJSON(3))
+			}))
+			defer server.Close()
+
+			seen := 0
+			err := solana.NewClient(server.URL).GetProgramAccountsStream(context.Background(), "prog", nil, func(solana.ProgramAccount) error {
+				seen++
+				return nil
+			})
+			if !errors.Is(err, solana.ErrResponseMismatch) {
+				t.Fatalf("expected ErrResponseMismatch, got %v", err)
+			}
+			if tc.callbacks != (seen > 0) {
+				t.Fatalf("unexpected %d callbacks", seen)
+			}
+		})
+	}
+}
+
+func TestGetProgramAccountsStreamDoesNotWaitForID(t *testing.T) {
+	firstSeen := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		// Written in Agave's {"jsonrpc","result","id"} order, holding the
+		// rest of the body back until the first account has been handed out.
+		fmt.Fprint(w, `{"jsonrpc":"2.0","result":[{"pubkey":"key0","account":{"lamports":1}},`)
+		w.(http.Flusher).Flush()
+		select {
+		case <-firstSeen:
+		case <-time.After(2 * time.Second):
+			t.Error("first account was not streamed before the body ended")
+		}
+		fmt.Fprint(w, `{"pubkey":"key1","account":{"lamports":2}}],"id":1}`)
+	}))
+	defer server.Close()
+
+	seen := 0
+	err := solana.NewClient(server.URL).GetProgramAccountsStream(context.Background(), "prog", nil, func(solana.ProgramAccount) error {
+		seen++
+		if seen == 1 {
+			close(firstSeen)
+		}
+		return nil
+	})
+	if err != nil || seen != 2 {
+		t.Fatalf("expected 2 accounts, got %d (err %v)", seen, err)
+	}
+}
+
+func TestGetProgramAccountsStreamIDFirst(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"id":1,"jsonrpc":"2.0","result":%s}`, accountsJSON(4))
+	}))
+	defer server.Close()
+
+	seen := 0
+	err := solana.NewClient(server.URL).GetProgramAccountsStream(context.Background(), "prog", nil, func(solana.ProgramAccount) error {
+		seen++
+		return nil
+	})
+	if err != nil || seen != 4 {
+		t.Fatalf("expected 4 accounts, got %d (err %v)", seen, err)
+	}
+}
+
+func TestGetProgramAccountsStreamRunsInterceptors(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/rewritten" || r.Header.Get("Authorization") != "Bearer token" {
+			http.Error(w, "unauthorized", http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, `{"jsonrpc":"2.0","result":%s,"id":1}`, accountsJSON(3))
+	}))
+	defer server.Close()
+
+	var observed *solana.RawResponse
+	auth := func(ctx context.Context, req *solana.Request, next solana.Invoker) (*solana.RawResponse, error) {
+		req.Endpoint = server.URL + "/rewritten"
+		req.Header.Set("Authorization", "Bearer token")
+		resp, err := next(ctx, req)
+		observed = resp
+		return resp, err
+	}
+
+	seen := 0
+	err := solana.NewClient(server.URL, solana.WithInterceptors(auth)).GetProgramAccountsStream(context.Background(), "prog", nil, func(solana.ProgramAccount) error {
+		seen++
+		return nil
+	})
+	if err != nil || seen != 3 {
+		t.Fatalf("expected 3 accounts, got %d (err %v)", seen, err)
+	}
+	if observed == nil || observed.StatusCode != http.StatusOK || observed.BodySize == 0 {
+		t.Fatalf("unexpected response seen by the interceptor %+v", observed)
+	}
+}
+
+func TestGetProgramAccountsStreamInterceptorAnswers(t *testing.T) {
+	canned := func(ctx context.Context, req *solana.Request, next solana.Invoker) (*solana.RawResponse, error) {
+		return &solana.RawResponse{ID: req.ID, Result: []byte(accountsJSON(2))}, nil
+	}
+
+	seen := 0
+	err := solana.NewClient("http://127.0.0.1:0", solana.WithInterceptors(canned)).GetProgramAccountsStream(context.Background(), "prog", nil, func(solana.ProgramAccount) error {
+		seen++
+		return nil
+	})
+	if err != nil || seen != 2 {
+		t.Fatalf("expected 2 accounts, got %d (err %v)", seen, err)
+	}
+}